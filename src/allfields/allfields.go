@@ -3,22 +3,250 @@ package allfields
 import (
 	"fmt"
 	"go/ast"
+	"go/token"
 	"go/types"
 	"reflect"
 
 	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/ctrlflow"
 
 	"github.com/upsun/vinego/src/utils"
 )
 
-func New() *analysis.Analyzer {
+// Maps the names of the fields set in the literal to their values.  Returns false for literals
+// mixing keyed and positional elements (rejected by the compiler).
+func literalValues(structType *types.Struct, literal *ast.CompositeLit) (map[string]ast.Expr, bool) {
+	out := map[string]ast.Expr{}
+	positional := 0
+	for _, e0 := range literal.Elts {
+		switch e := e0.(type) {
+		case *ast.KeyValueExpr:
+			switch k := e.Key.(type) {
+			case *ast.Ident:
+				out[k.Name] = e.Value
+			}
+		default:
+			positional += 1
+		}
+	}
+	if positional > 0 && positional != len(literal.Elts) {
+		return nil, false
+	}
+	// Positional elements are assigned to fields in declaration order
+	for i := 0; i < positional && i < structType.NumFields(); i++ {
+		out[structType.Field(i).Name()] = literal.Elts[i]
+	}
+	return out, true
+}
+
+// Fields are required unless marked `optional` or part of a `oneof` group
+func fieldRequired(structType *types.Struct, i int) bool {
+	tag := reflect.StructTag(structType.Tag(i))
+	return tag.Get("optional") != "1" && tag.Get("oneof") == ""
+}
+
+// Whether the field can be set from the package: unexported fields of other packages' types can't
+func fieldSettable(pkg *types.Package, field *types.Var) bool {
+	return field.Exported() || field.Pkg() == pkg
+}
+
+// Returns the required fields missing from the literal that the package can set, in declaration
+// order
+func missingFields(
+	pkg *types.Package,
+	structType *types.Struct,
+	values map[string]ast.Expr,
+	exempt map[string]bool,
+) []*types.Var {
+	out := []*types.Var{}
+	for i := 0; i < structType.NumFields(); i++ {
+		if !fieldRequired(structType, i) {
+			continue
+		}
+		field := structType.Field(i)
+		if !fieldSettable(pkg, field) {
+			continue
+		}
+		if _, present := values[field.Name()]; present || exempt[field.Name()] {
+			continue
+		}
+		utils.Append(&out, field)
+	}
+	return out
+}
+
+//vinego:check allfields
+type deepMissing struct {
+	// Paths of missing fields, from the root type
+	Paths []string
+	// Edits adding the missing fields to each incomplete literal
+	Edits []analysis.TextEdit
+	// False if some incomplete literal couldn't be fixed
+	Fixable bool
+}
+
+// Looks for missing required fields in struct typed fields of the literal, recursing through
+// nested literals.  Nested literals are added to covered so they're not checked again on their own.
+func findDeepMissing(
+	p *analysis.Pass,
+	file *ast.File,
+	configured map[string][]string,
+	structType *types.Struct,
+	values map[string]ast.Expr,
+	path string,
+	covered map[*ast.CompositeLit]bool,
+	out *deepMissing,
+) {
+	for i := 0; i < structType.NumFields(); i++ {
+		if !fieldRequired(structType, i) {
+			continue
+		}
+		field := structType.Field(i)
+		if !fieldSettable(p.Pkg, field) {
+			continue
+		}
+		value, present := values[field.Name()]
+		if !present {
+			continue
+		}
+		findDeepMissingIn(p, file, configured, value, path+"."+field.Name(), covered, out)
+	}
+}
+
+// Checks a value nested in a deep checked literal if it's a struct literal, descending into the
+// elements of slice, array and map literals.  Fields exempt for the nested type are skipped.
+func findDeepMissingIn(
+	p *analysis.Pass,
+	file *ast.File,
+	configured map[string][]string,
+	value ast.Expr,
+	path string,
+	covered map[*ast.CompositeLit]bool,
+	out *deepMissing,
+) {
+	value = ast.Unparen(value)
+	if unary, isUnary := value.(*ast.UnaryExpr); isUnary && unary.Op == token.AND {
+		value = ast.Unparen(unary.X)
+	}
+	nested, isLiteral := value.(*ast.CompositeLit)
+	if !isLiteral {
+		// Initialized some other way, can't see into it
+		return
+	}
+	nestedType := p.TypesInfo.TypeOf(nested)
+	if nestedType == nil {
+		return
+	}
+	if pointer, isPointer := nestedType.Underlying().(*types.Pointer); isPointer {
+		// Elided `&T{}` elements
+		nestedType = pointer.Elem()
+	}
+	switch t := nestedType.Underlying().(type) {
+	case *types.Struct:
+		covered[nested] = true
+		nestedValues, valid := literalValues(t, nested)
+		if !valid {
+			return
+		}
+		exempt := map[string]bool{}
+		if named := literalNamed(nestedType); named != nil {
+			_, exempt = typeChecks(p, configured, named)
+		}
+		missing := missingFields(p.Pkg, t, nestedValues, exempt)
+		if len(missing) > 0 {
+			for _, m := range missing {
+				utils.Append(&out.Paths, path+"."+m.Name())
+			}
+			if edit, ok := missingFieldsEdit(p, file, nested, missing); ok {
+				utils.Append(&out.Edits, edit)
+			} else {
+				out.Fixable = false
+			}
+		}
+		findDeepMissing(p, file, configured, t, nestedValues, path, covered, out)
+	case *types.Slice, *types.Array, *types.Map:
+		for i, elt := range nested.Elts {
+			index := fmt.Sprint(i)
+			if kv, isKv := elt.(*ast.KeyValueExpr); isKv {
+				index = types.ExprString(kv.Key)
+				elt = kv.Value
+			}
+			findDeepMissingIn(p, file, configured, elt, path+"["+index+"]", covered, out)
+		}
+	}
+}
+
+// Returns the named type constructed by a literal of type t, looking through aliases and pointers
+// (for elided `&T{}` elements).  Tags are looked up on the origin of instantiated generic types.
+func literalNamed(t types.Type) *types.Named {
+	t = types.Unalias(t)
+	if pointer, isPointer := t.(*types.Pointer); isPointer {
+		t = types.Unalias(pointer.Elem())
+	}
+	named, _ := t.(*types.Named)
+	return named
+}
+
+//vinego:check allfields
+type TypeSettings struct {
+	// Fully qualified type name, ex: `net/http.Server`
+	Name string `json:"name"`
+	// Fields exempt from the check, like `optional`
+	Exempt []string `json:"exempt" optional:"1"`
+}
+
+//vinego:check allfields
+type Settings struct {
+	// Types checked as if they were tagged with `//vinego:check allfields`
+	Types []TypeSettings `json:"types"`
+	// Also accept tagged struct variables declared without a value if all required fields are
+	// assigned on every path before the variable is used
+	Flow bool `json:"flow" optional:"1"`
+	// Recognize legacy `check:name` tags in doc comments, set from the top level setting
+	LegacyDirectives bool `json:"-" optional:"1"`
+}
+
+// Returns the checks enabled for the type and fields exempt from them
+func typeChecks(p *analysis.Pass, configured map[string][]string, named *types.Named) (utils.ChecksFact, map[string]bool) {
+	obj := named.Origin().Obj()
+	enabledChecks := utils.GetTags(p, obj)
+	exempt := map[string]bool{}
+	if exemptFields, configured := configured[utils.QualifiedName(obj)]; configured && !enabledChecks.Has("allfields") {
+		enabledChecks["allfields"] = utils.CheckArgs{}
+		for _, field := range exemptFields {
+			exempt[field] = true
+		}
+	}
+	for _, check := range []string{"allfields", "allfields-deep"} {
+		for _, field := range enabledChecks[check]["except"] {
+			exempt[field] = true
+		}
+	}
+	return enabledChecks, exempt
+}
+
+func New(settings Settings) *analysis.Analyzer {
+	configured := map[string][]string{}
+	for _, t := range settings.Types {
+		configured[t.Name] = t.Exempt
+	}
+	requires := []*analysis.Analyzer{}
+	if settings.Flow {
+		utils.Append(&requires, ctrlflow.Analyzer)
+	}
 	return &analysis.Analyzer{
 		Name:      "allfields",
 		Doc:       "_",
 		FactTypes: []analysis.Fact{new(utils.ChecksFact)},
+		Requires:  requires,
 		Run: func(p *analysis.Pass) (any, error) {
-			utils.ScanTypeTags(p)
+			utils.ScanTags(p, settings.LegacyDirectives)
+			if settings.Flow {
+				checkFlow(p, configured)
+			}
 			for _, file := range p.Files {
+				// Nested literals already checked as part of a deep check
+				covered := map[*ast.CompositeLit]bool{}
 				ast.Inspect(file, func(n ast.Node) bool {
 					literal, isCompLiteral := n.(*ast.CompositeLit)
 					if !isCompLiteral {
@@ -28,24 +256,16 @@ func New() *analysis.Analyzer {
 					if litType == nil {
 						return true
 					}
-					var litType1 *types.Named = nil
-					if litType2, isPointer := litType.(*types.Pointer); isPointer {
-						if litType3, isNamed := litType2.Elem().(*types.Named); isNamed {
-							litType1 = litType3
-						}
-					} else if litType2, isNamed := litType.(*types.Named); isNamed {
-						litType1 = litType2
-					}
+					litType1 := literalNamed(litType)
 					if litType1 == nil {
 						return true
 					}
-					enabledChecks := new(utils.ChecksFact)
-					p.ImportObjectFact(litType1.Obj(), enabledChecks)
-					if len(*enabledChecks) == 0 {
+					enabledChecks, exempt := typeChecks(p, configured, litType1)
+					if len(enabledChecks) == 0 {
 						return true
 					}
 
-					if (*enabledChecks)["allfields"] {
+					if enabledChecks.Has("allfields") || enabledChecks.Has("allfields-deep") {
 						structType, isStruct := litType1.Underlying().(*types.Struct)
 						if !isStruct {
 							p.Report(analysis.Diagnostic{
@@ -55,43 +275,70 @@ func New() *analysis.Analyzer {
 							})
 							return true
 						}
-						remaining := map[string]bool{}
-						for i := 0; i < structType.NumFields(); i++ {
-							if reflect.StructTag(structType.Tag(i)).Get("optional") == "1" {
-								continue
-							}
-							remaining[structType.Field(i).Name()] = true
+						values, valid := literalValues(structType, literal)
+						if !valid {
+							return true
 						}
+						for _, violation := range constraintViolations(structType, values) {
+							p.Report(analysis.Diagnostic{
+								Pos:      n.Pos(),
+								Category: "error",
+								Message:  violation,
+							})
+						}
+						if covered[literal] {
+							return true
+						}
+						missing := missingFields(p.Pkg, structType, values, exempt)
 
-						nonKv := false
-						for _, e0 := range literal.Elts {
-							switch e := e0.(type) {
-							case *ast.KeyValueExpr:
-								switch k := e.Key.(type) {
-								case *ast.Ident:
-									delete(remaining, k.Name)
-								}
-							default:
-								// TODO handle keyless fields?
-								nonKv = true
+						niceMissing := []string{}
+						deep := deepMissing{
+							Paths:   []string{},
+							Edits:   []analysis.TextEdit{},
+							Fixable: true,
+						}
+						if enabledChecks.Has("allfields-deep") {
+							root := litType1.Obj().Name()
+							for _, field := range missing {
+								utils.Append(&niceMissing, root+"."+field.Name())
+							}
+							findDeepMissing(p, file, configured, structType, values, root, covered, &deep)
+							utils.Append(&niceMissing, deep.Paths...)
+						} else {
+							for _, field := range missing {
+								utils.Append(&niceMissing, field.Name())
 							}
 						}
+						if len(niceMissing) == 0 {
+							return true
+						}
 
-						if !nonKv && len(remaining) > 0 {
-							niceRemaining := []string{}
-							for k := range remaining {
-								niceRemaining = append(niceRemaining, k)
+						diagnostic := analysis.Diagnostic{
+							Pos:      n.Pos(),
+							Category: "error",
+							Message:  fmt.Sprintf("Missing required fields in struct literal: %v", niceMissing),
+						}
+						edits := deep.Edits
+						if len(missing) > 0 {
+							if edit, ok := missingFieldsEdit(p, file, literal, missing); ok {
+								edits = append([]analysis.TextEdit{edit}, edits...)
+							} else {
+								deep.Fixable = false
 							}
-							p.Report(analysis.Diagnostic{
-								Pos:      n.Pos(),
-								Category: "error",
-								Message:  fmt.Sprintf("Missing required fields in struct literal: %v", niceRemaining),
+						}
+						if deep.Fixable {
+							utils.Append(&diagnostic.SuggestedFixes, analysis.SuggestedFix{
+								Message:   "Add missing required fields",
+								TextEdits: edits,
 							})
 						}
+						p.Report(diagnostic)
 					}
 
 					return true
 				})
+				checkNoZero(p, file)
+				checkConstraintTags(p, configured, file)
 			}
 			return nil, nil
 		},
@@ -7,6 +7,22 @@ import (
 )
 
 func TestAnalyzers(t *testing.T) {
-	allFieldsAnalyzer := New()
+	allFieldsAnalyzer := New(Settings{
+		Types: []TypeSettings{},
+	})
 	testutils.RunTests(t, allFieldsAnalyzer, nil)
 }
+
+func TestSettings(t *testing.T) {
+	allFieldsAnalyzer := New(Settings{
+		Types: []TypeSettings{
+			{Name: "image.Point"},
+			{Name: "image.Rectangle", Exempt: []string{"Max"}},
+			{Name: "time.Timer"},
+			{Name: "configuredpkgbad/dep.Conf"},
+		},
+		Flow:             true,
+		LegacyDirectives: true,
+	})
+	testutils.RunTestsIn(t, "testdata/settings", allFieldsAnalyzer, nil)
+}
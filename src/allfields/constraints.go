@@ -0,0 +1,135 @@
+package allfields
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/upsun/vinego/src/utils"
+)
+
+// Checks the field group tags against the fields set in the literal:
+//
+//   - `oneof:"group"`: exactly one of the fields in the group must be set
+//   - `requires:"A,B"`: if the field is set, fields A and B must be set too
+//
+// Returns a message for each violated constraint.
+func constraintViolations(structType *types.Struct, values map[string]ast.Expr) []string {
+	out := []string{}
+	groupOrder := []string{}
+	groups := map[string][]string{}
+	for i := 0; i < structType.NumFields(); i++ {
+		field := structType.Field(i)
+		tag := reflect.StructTag(structType.Tag(i))
+		if group := tag.Get("oneof"); group != "" {
+			if _, seen := groups[group]; !seen {
+				utils.Append(&groupOrder, group)
+			}
+			groups[group] = append(groups[group], field.Name())
+		}
+		if _, present := values[field.Name()]; !present {
+			continue
+		}
+		requires := tag.Get("requires")
+		if requires == "" {
+			continue
+		}
+		missing := []string{}
+		for required := range strings.SplitSeq(requires, ",") {
+			required = strings.TrimSpace(required)
+			if !hasField(structType, required) {
+				// Reported at the declaration
+				continue
+			}
+			if _, present := values[required]; !present {
+				utils.Append(&missing, required)
+			}
+		}
+		if len(missing) > 0 {
+			utils.Append(&out, fmt.Sprintf("Field %s requires %v which are missing", field.Name(), missing))
+		}
+	}
+	for _, group := range groupOrder {
+		provided := []string{}
+		for _, name := range groups[group] {
+			if _, present := values[name]; present {
+				utils.Append(&provided, name)
+			}
+		}
+		if len(provided) != 1 {
+			utils.Append(&out, fmt.Sprintf(
+				"Exactly one of oneof group %s %v must be set, got %v",
+				group,
+				groups[group],
+				provided,
+			))
+		}
+	}
+	return out
+}
+
+func hasField(structType *types.Struct, name string) bool {
+	for i := 0; i < structType.NumFields(); i++ {
+		if structType.Field(i).Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Reports names in `requires` tags that aren't fields of the struct, for the struct types declared
+// in the file that are checked
+func checkConstraintTags(p *analysis.Pass, configured map[string][]string, file *ast.File) {
+	ast.Inspect(file, func(n ast.Node) bool {
+		spec, isTypeSpec := n.(*ast.TypeSpec)
+		if !isTypeSpec {
+			return true
+		}
+		syntax, isStruct := spec.Type.(*ast.StructType)
+		if !isStruct {
+			return true
+		}
+		typeName, isTypeName := p.TypesInfo.Defs[spec.Name].(*types.TypeName)
+		if !isTypeName {
+			return true
+		}
+		named, isNamed := typeName.Type().(*types.Named)
+		if !isNamed {
+			return true
+		}
+		enabledChecks, _ := typeChecks(p, configured, named)
+		if !enabledChecks.Has("allfields") && !enabledChecks.Has("allfields-deep") {
+			return true
+		}
+		structType := named.Underlying().(*types.Struct)
+		for _, field := range syntax.Fields.List {
+			if field.Tag == nil {
+				continue
+			}
+			tag, err := strconv.Unquote(field.Tag.Value)
+			if err != nil {
+				continue
+			}
+			requires := reflect.StructTag(tag).Get("requires")
+			if requires == "" {
+				continue
+			}
+			for required := range strings.SplitSeq(requires, ",") {
+				required = strings.TrimSpace(required)
+				if !hasField(structType, required) {
+					p.Report(analysis.Diagnostic{
+						Pos:      field.Tag.Pos(),
+						Category: "error",
+						Message:  fmt.Sprintf("Required field %s in requires tag isn't a field of %s", required, spec.Name.Name),
+					})
+				}
+			}
+		}
+		return true
+	})
+}
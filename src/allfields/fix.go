@@ -0,0 +1,203 @@
+package allfields
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/upsun/vinego/src/utils"
+)
+
+// Whether the type can be written in the package: it doesn't refer to unexported types or struct
+// fields of other packages
+func typeAccessible(pkg *types.Package, t types.Type) bool {
+	accessible := func(obj types.Object) bool {
+		return obj.Pkg() == nil || obj.Pkg() == pkg || obj.Exported()
+	}
+	typeArgsAccessible := func(args *types.TypeList) bool {
+		for i := 0; i < args.Len(); i++ {
+			if !typeAccessible(pkg, args.At(i)) {
+				return false
+			}
+		}
+		return true
+	}
+	switch t := t.(type) {
+	case *types.Alias:
+		// Written with the alias name
+		return accessible(t.Obj()) && typeArgsAccessible(t.TypeArgs())
+	case *types.Named:
+		return accessible(t.Obj()) && typeArgsAccessible(t.TypeArgs())
+	case *types.Pointer:
+		return typeAccessible(pkg, t.Elem())
+	case *types.Slice:
+		return typeAccessible(pkg, t.Elem())
+	case *types.Array:
+		return typeAccessible(pkg, t.Elem())
+	case *types.Chan:
+		return typeAccessible(pkg, t.Elem())
+	case *types.Map:
+		return typeAccessible(pkg, t.Key()) && typeAccessible(pkg, t.Elem())
+	case *types.Struct:
+		for i := 0; i < t.NumFields(); i++ {
+			if !accessible(t.Field(i)) || !typeAccessible(pkg, t.Field(i).Type()) {
+				return false
+			}
+		}
+		return true
+	case *types.Tuple:
+		for i := 0; i < t.Len(); i++ {
+			if !typeAccessible(pkg, t.At(i).Type()) {
+				return false
+			}
+		}
+		return true
+	case *types.Signature:
+		return typeAccessible(pkg, t.Params()) && typeAccessible(pkg, t.Results())
+	case *types.Interface:
+		for i := 0; i < t.NumExplicitMethods(); i++ {
+			method := t.ExplicitMethod(i)
+			if !accessible(method) || !typeAccessible(pkg, method.Type()) {
+				return false
+			}
+		}
+		for i := 0; i < t.NumEmbeddeds(); i++ {
+			if !typeAccessible(pkg, t.EmbeddedType(i)) {
+				return false
+			}
+		}
+		return true
+	default:
+		return true
+	}
+}
+
+// Produces an expression for the zero value of t as it would be written in the file, or false if
+// the type can't be referred to from the file.
+func zeroValue(pkg *types.Package, t types.Type, qualifier types.Qualifier) (string, bool) {
+	if typeParam, isTypeParam := t.(*types.TypeParam); isTypeParam {
+		return "*new(" + types.TypeString(typeParam, qualifier) + ")", true
+	}
+	if !typeAccessible(pkg, t) {
+		return "", false
+	}
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		var lit string
+		switch {
+		case u.Info()&types.IsBoolean != 0:
+			lit = "false"
+		case u.Info()&types.IsString != 0:
+			lit = `""`
+		case u.Info()&types.IsNumeric != 0:
+			lit = "0"
+		case u.Kind() == types.UnsafePointer:
+			return "nil", true
+		default:
+			return "", false
+		}
+		if _, isBasic := types.Unalias(t).(*types.Basic); isBasic {
+			return lit, true
+		}
+		// Explicit conversion so the fix doesn't produce implicit casts
+		return types.TypeString(t, qualifier) + "(" + lit + ")", true
+	case *types.Pointer, *types.Slice, *types.Map, *types.Chan, *types.Signature, *types.Interface:
+		return "nil", true
+	case *types.Struct, *types.Array:
+		return types.TypeString(t, qualifier) + "{}", true
+	default:
+		return "", false
+	}
+}
+
+// Returns the whitespace at the start of the line containing pos
+func lineIndent(tokFile *token.File, source []byte, pos token.Pos) string {
+	start := tokFile.Offset(tokFile.LineStart(tokFile.Line(pos)))
+	end := start
+	for end < len(source) && (source[end] == ' ' || source[end] == '\t') {
+		end += 1
+	}
+	return string(source[start:end])
+}
+
+// Creates an edit adding the missing fields with zero values to the literal, matching the single or
+// multi-line layout of the literal.
+func missingFieldsEdit(
+	p *analysis.Pass,
+	file *ast.File,
+	literal *ast.CompositeLit,
+	missing []*types.Var,
+) (analysis.TextEdit, bool) {
+	for _, e := range literal.Elts {
+		if _, isKv := e.(*ast.KeyValueExpr); !isKv {
+			return analysis.TextEdit{}, false
+		}
+	}
+	tokFile := p.Fset.File(literal.Pos())
+	if tokFile == nil {
+		return analysis.TextEdit{}, false
+	}
+	source, err := p.ReadFile(tokFile.Name())
+	if err != nil {
+		return analysis.TextEdit{}, false
+	}
+
+	unresolved := false
+	qualifier := utils.FileQualifier(p, file, &unresolved)
+	fields := []string{}
+	for _, field := range missing {
+		if !fieldSettable(p.Pkg, field) {
+			return analysis.TextEdit{}, false
+		}
+		zero, ok := zeroValue(p.Pkg, field.Type(), qualifier)
+		if !ok || unresolved {
+			return analysis.TextEdit{}, false
+		}
+		utils.Append(&fields, field.Name()+": "+zero)
+	}
+
+	var lastPos token.Pos
+	if len(literal.Elts) > 0 {
+		lastPos = utils.Last(literal.Elts).End()
+	} else {
+		lastPos = literal.Lbrace
+	}
+	var edit analysis.TextEdit
+	if tokFile.Line(lastPos) == tokFile.Line(literal.Rbrace) {
+		text := strings.Join(fields, ", ")
+		if len(literal.Elts) > 0 {
+			between := strings.TrimSpace(string(source[tokFile.Offset(lastPos):tokFile.Offset(literal.Rbrace)]))
+			if between == "," {
+				text = " " + text
+			} else {
+				text = ", " + text
+			}
+		}
+		edit = analysis.TextEdit{
+			Pos:     literal.Rbrace,
+			End:     literal.Rbrace,
+			NewText: []byte(text),
+		}
+	} else {
+		var indent string
+		if len(literal.Elts) > 0 {
+			indent = lineIndent(tokFile, source, utils.Last(literal.Elts).Pos())
+		} else {
+			indent = lineIndent(tokFile, source, literal.Rbrace) + "\t"
+		}
+		text := strings.Builder{}
+		for _, field := range fields {
+			text.WriteString(indent + field + ",\n")
+		}
+		lineStart := tokFile.LineStart(tokFile.Line(literal.Rbrace))
+		edit = analysis.TextEdit{
+			Pos:     lineStart,
+			End:     lineStart,
+			NewText: []byte(text.String()),
+		}
+	}
+	return edit, true
+}
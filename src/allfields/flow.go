@@ -0,0 +1,82 @@
+package allfields
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/ctrlflow"
+
+	"github.com/upsun/vinego/src/utils"
+	"github.com/upsun/vinego/src/varinit"
+)
+
+// Uses the varinit engine to follow variables of checked types declared without a value, tracking
+// assignments to each required field.  Uses of the variable before all required fields have been
+// assigned on every path are reported.
+func checkFlow(p *analysis.Pass, configured map[string][]string) {
+	flows := varinit.NewFlows(p, p.ResultOf[ctrlflow.Analyzer].(*ctrlflow.CFGs), nil)
+	a := varinit.NewAnalysis(p, flows, &varinit.Tracker{
+		Fields: func(p *analysis.Pass, v *types.Var) ([]string, bool) {
+			named, isNamed := types.Unalias(v.Type()).(*types.Named)
+			if !isNamed {
+				return nil, false
+			}
+			enabledChecks, exempt := typeChecks(p, configured, named)
+			if !enabledChecks.Has("allfields") && !enabledChecks.Has("allfields-deep") {
+				return nil, false
+			}
+			structType, isStruct := named.Underlying().(*types.Struct)
+			if !isStruct {
+				return nil, false
+			}
+			missing := missingFields(p.Pkg, structType, map[string]ast.Expr{}, exempt)
+			if len(missing) == 0 {
+				return nil, false
+			}
+			fields := []string{}
+			for _, field := range missing {
+				utils.Append(&fields, field.Name())
+			}
+			return fields, true
+		},
+		Message: func(name string, uninitialized []varinit.UninitializedField) string {
+			fields := []string{}
+			branches := []string{}
+			for _, field := range uninitialized {
+				utils.Append(&fields, field.Field)
+				utils.Append(&branches, fmt.Sprintf(
+					"`%s` isn't assigned in the following branches:\n%s\n",
+					field.Field,
+					strings.Join(field.Branches, "\n"),
+				))
+			}
+			return fmt.Sprintf(
+				"Missing required fields in `%s` when used: %v\n%s",
+				name,
+				fields,
+				strings.Join(branches, ""),
+			)
+		},
+		InitializedBy: varinit.IsInitializer,
+	}, &varinit.OutParams{
+		// Literals passed to unmarshalling functions and the like are filled in
+		Get:        varinit.KnownOutParamsOnly,
+		Permissive: true,
+	})
+	for _, file := range p.Files {
+		for _, decl := range file.Decls {
+			funcDecl, isFuncDecl := decl.(*ast.FuncDecl)
+			if !isFuncDecl {
+				continue
+			}
+			flow := flows.FuncDecl(funcDecl)
+			if flow == nil {
+				continue
+			}
+			varinit.EvalFunc(a, flow, funcDecl.Type, nil)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package allfields
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/upsun/vinego/src/utils"
+)
+
+// Returns the object of the type if it's marked with `//vinego:check nozero`
+func noZeroType(p *analysis.Pass, t types.Type) *types.TypeName {
+	if t == nil {
+		return nil
+	}
+	named, isNamed := types.Unalias(t).(*types.Named)
+	if !isNamed {
+		return nil
+	}
+	obj := named.Origin().Obj()
+	if !utils.GetTags(p, obj).Has("nozero") {
+		return nil
+	}
+	return obj
+}
+
+// Constructors are functions (not methods) in the type's package named `New...` or `new...` that
+// return the type or a pointer to it
+func isConstructor(p *analysis.Pass, decl *ast.FuncDecl, obj *types.TypeName) bool {
+	if decl == nil || decl.Recv != nil || obj.Pkg() != p.Pkg {
+		return false
+	}
+	if !isConstructorName(decl.Name.Name) {
+		return false
+	}
+	funcObj, isFunc := p.TypesInfo.Defs[decl.Name].(*types.Func)
+	if !isFunc {
+		return false
+	}
+	results := funcObj.Signature().Results()
+	for i := 0; i < results.Len(); i++ {
+		named := literalNamed(results.At(i).Type())
+		if named != nil && named.Origin().Obj() == obj {
+			return true
+		}
+	}
+	return false
+}
+
+// Whether the name is `New` or `new`, optionally followed by a capitalized word: `NewClient` but not
+// `Newsletter`
+func isConstructorName(name string) bool {
+	rest, hasPrefix := strings.CutPrefix(name, "New")
+	if !hasPrefix {
+		rest, hasPrefix = strings.CutPrefix(name, "new")
+	}
+	if !hasPrefix {
+		return false
+	}
+	first, _ := utf8.DecodeRuneInString(rest)
+	return rest == "" || unicode.IsUpper(first)
+}
+
+// Reports zero value construction of types marked nozero outside of their constructors:
+// `var t T`, `new(T)`, `T{}` and named results `(t T)`
+func checkNoZero(p *analysis.Pass, file *ast.File) {
+	utils.WalkWithCrumbs(file, func(n0 ast.Node, crumbs []ast.Node) bool {
+		report := func(pos token.Pos, obj *types.TypeName) {
+			var inFunc *ast.FuncDecl = nil
+			for _, crumb := range crumbs {
+				if f, isFuncDecl := crumb.(*ast.FuncDecl); isFuncDecl {
+					inFunc = f
+				}
+			}
+			if isConstructor(p, inFunc, obj) {
+				return
+			}
+			p.Report(analysis.Diagnostic{
+				Pos:      pos,
+				Category: "error",
+				Message:  fmt.Sprintf("Zero value construction of %s, which is marked nozero", obj.Name()),
+			})
+		}
+		var obj *types.TypeName
+		switch n := n0.(type) {
+		case *ast.ValueSpec:
+			if n.Type == nil || len(n.Values) > 0 {
+				return true
+			}
+			obj = noZeroType(p, p.TypesInfo.TypeOf(n.Type))
+		case *ast.FuncType:
+			// Named results start as zero values, which a bare return hands out
+			if n.Results == nil {
+				return true
+			}
+			for _, field := range n.Results.List {
+				if len(field.Names) == 0 {
+					continue
+				}
+				if resultObj := noZeroType(p, p.TypesInfo.TypeOf(field.Type)); resultObj != nil {
+					report(field.Pos(), resultObj)
+				}
+			}
+			return true
+		case *ast.CallExpr:
+			ident, isIdent := ast.Unparen(n.Fun).(*ast.Ident)
+			if !isIdent || len(n.Args) != 1 {
+				return true
+			}
+			if _, isBuiltin := p.TypesInfo.Uses[ident].(*types.Builtin); !isBuiltin || ident.Name != "new" {
+				return true
+			}
+			arg := p.TypesInfo.Types[n.Args[0]]
+			if !arg.IsType() {
+				// `new(expr)` copies a value
+				return true
+			}
+			obj = noZeroType(p, arg.Type)
+		case *ast.CompositeLit:
+			if len(n.Elts) > 0 {
+				return true
+			}
+			named := literalNamed(p.TypesInfo.TypeOf(n))
+			if named == nil {
+				return true
+			}
+			obj = noZeroType(p, named)
+		}
+		if obj != nil {
+			report(n0.Pos(), obj)
+		}
+		return true
+	})
+}
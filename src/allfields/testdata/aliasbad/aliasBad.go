@@ -0,0 +1,20 @@
+package aliasbad
+
+//vinego:check allfields
+type MyStruct struct { // want MyStruct:".*"
+	X int
+}
+
+type Alias = MyStruct
+
+type AliasAlias = Alias
+
+func consume(x any) {}
+
+func main() {
+	consume(Alias{})      // want "Missing required"
+	consume(AliasAlias{}) // want "Missing required"
+	consume([]Alias{
+		{}, // want "Missing required"
+	})
+}
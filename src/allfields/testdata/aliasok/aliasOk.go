@@ -0,0 +1,20 @@
+package aliasok
+
+//vinego:check allfields
+type MyStruct struct { // want MyStruct:".*"
+	X int
+}
+
+type Alias = MyStruct
+
+type AliasAlias = Alias
+
+func consume(x any) {}
+
+func main() {
+	consume(Alias{X: 1})
+	consume(AliasAlias{X: 2})
+	consume([]Alias{
+		{X: 3},
+	})
+}
@@ -0,0 +1,39 @@
+package constraintsbad
+
+//vinego:check allfields
+type Options struct { // want Options:".*"
+	Name    string
+	File    string `oneof:"source"`
+	URL     string `oneof:"source"`
+	Inline  []byte `oneof:"source"`
+	TLSCert string `optional:"1" requires:"TLSKey, TLSCA"`
+	TLSKey  string `optional:"1"`
+	TLSCA   string `optional:"1"`
+}
+
+//vinego:check allfields
+type Mirror struct { // want Mirror:".*"
+	Primary   string `optional:"1" requires:"Secndary"` // want "Required field Secndary in requires tag isn't a field of Mirror"
+	Secondary string `optional:"1"`
+}
+
+func consume(x Options) {}
+
+func main() {
+	consume(Options{ // want "Exactly one of oneof group source \\[File URL Inline\\] must be set, got \\[\\]"
+		Name: "x",
+	})
+	consume(Options{ // want "Exactly one of oneof group source \\[File URL Inline\\] must be set, got \\[File URL\\]"
+		Name: "x",
+		File: "f",
+		URL:  "u",
+	})
+	consume(Options{ // want "Field TLSCert requires \\[TLSCA\\] which are missing"
+		Name:    "x",
+		File:    "f",
+		TLSCert: "c",
+		TLSKey:  "k",
+	})
+	// The unknown name is only reported at the declaration
+	_ = Mirror{Primary: "p"}
+}
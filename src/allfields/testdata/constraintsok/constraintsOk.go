@@ -0,0 +1,25 @@
+package constraintsok
+
+//vinego:check allfields
+type Options struct { // want Options:".*"
+	Name    string
+	File    string `oneof:"source"`
+	URL     string `oneof:"source"`
+	TLSCert string `optional:"1" requires:"TLSKey"`
+	TLSKey  string `optional:"1"`
+}
+
+func consume(x Options) {}
+
+func main() {
+	consume(Options{
+		Name: "x",
+		URL:  "u",
+	})
+	consume(Options{
+		Name:    "x",
+		File:    "f",
+		TLSCert: "c",
+		TLSKey:  "k",
+	})
+}
@@ -0,0 +1,25 @@
+package declarationfacts
+
+// Directives are exported as facts on the declarations the check can be placed on
+
+//vinego:check noreturn
+func F() {} // want F:"checks\\(noreturn\\)"
+
+//vinego:check nozero
+func G() {}
+
+type S struct {
+	//vinego:check nozero
+	X int
+}
+
+//vinego:check initializer
+func (s *S) Init() {} // want Init:"checks\\(initializer\\)"
+
+//vinego:check initializer
+func (s S) M() {}
+
+type I interface {
+	//vinego:check noreturn
+	N() // want N:"checks\\(noreturn\\)"
+}
@@ -0,0 +1,58 @@
+package deepbad
+
+type Base struct {
+	ID int
+}
+
+type TLS struct {
+	Cert string
+	Key  string `optional:"1"`
+}
+
+type Server struct {
+	Host string
+	Port int
+	TLS  TLS
+}
+
+//vinego:check allfields-deep
+type Config struct { // want Config:".*"
+	Base
+	Name   string
+	Server *Server
+	Debug  bool `optional:"1"`
+}
+
+func consume(x Config) {}
+
+type Route struct {
+	Path    string
+	Handler string
+}
+
+//vinego:check allfields-deep
+type Router struct { // want Router:".*"
+	Routes []Route
+	ByName map[string]*Route
+}
+
+func consumeRouter(x Router) {}
+
+func main() {
+	consume(Config{ // want "Missing required fields in struct literal: \\[Config.Base\\]"
+		Name:   "x",
+		Server: &Server{Host: "h", Port: 1, TLS: TLS{Cert: "c"}},
+	})
+	consume(Config{ // want "Missing required fields in struct literal: \\[Config.Base.ID Config.Server.Port Config.Server.TLS.Cert\\]"
+		Base: Base{},
+		Name: "x",
+		Server: &Server{
+			Host: "h",
+			TLS:  TLS{},
+		},
+	})
+	consumeRouter(Router{ // want "Missing required fields in struct literal: \\[Router.Routes\\[1\\].Handler Router.ByName\\[\"a\"\\].Path\\]"
+		Routes: []Route{{Path: "/", Handler: "h"}, {Path: "/x"}},
+		ByName: map[string]*Route{"a": {Handler: "h"}},
+	})
+}
@@ -0,0 +1,68 @@
+package deepok
+
+import "time"
+
+type Base struct {
+	ID int
+}
+
+type Server struct {
+	Host string
+	Port int
+}
+
+//vinego:check allfields-deep
+type Config struct { // want Config:".*"
+	Base
+	Name   string
+	Server Server
+}
+
+func defaultServer() Server {
+	return Server{Host: "h"}
+}
+
+//vinego:check allfields except=Comment
+type Entry struct { // want Entry:".*"
+	Name    string
+	Comment string
+}
+
+// Nested types' exempt fields can be left out
+//
+//vinego:check allfields-deep
+type Catalog struct { // want Catalog:".*"
+	Main    Entry
+	Entries []Entry
+}
+
+// Unexported fields of other packages' types can't be set
+//
+//vinego:check allfields-deep
+type Schedule struct { // want Schedule:".*"
+	Created time.Time
+	Timer   *time.Timer
+}
+
+func consume(x any) {}
+
+func main() {
+	consume(Config{
+		Base:   Base{ID: 1},
+		Name:   "x",
+		Server: Server{Host: "h", Port: 1},
+	})
+	consume(Config{
+		Base:   Base{ID: 1},
+		Name:   "x",
+		Server: defaultServer(),
+	})
+	consume(Catalog{
+		Main:    Entry{Name: "a"},
+		Entries: []Entry{{Name: "b"}},
+	})
+	consume(Schedule{
+		Created: time.Time{},
+		Timer:   &time.Timer{C: make(chan time.Time)},
+	})
+}
@@ -0,0 +1,14 @@
+package exceptok
+
+//vinego:check allfields except=Y,Z
+type MyStruct struct { // want MyStruct:".*"
+	X int
+	Y int
+	Z int
+}
+
+func consume(x MyStruct) {}
+
+func main() {
+	consume(MyStruct{X: 1})
+}
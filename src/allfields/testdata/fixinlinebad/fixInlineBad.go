@@ -0,0 +1,31 @@
+package fixinlinebad
+
+import (
+	tm "time"
+)
+
+type Level int
+
+type Inner struct {
+	A int
+}
+
+//vinego:check allfields
+type MyStruct struct { // want MyStruct:".*"
+	X int
+	Y string
+	Z bool
+	L Level
+	D tm.Duration
+	P *int
+	S []string
+	I Inner
+	O int `optional:"1"`
+}
+
+func consume(x MyStruct) {}
+
+func main() {
+	consume(MyStruct{})             // want "Missing required"
+	consume(MyStruct{X: 4, Y: "y"}) // want "Missing required"
+}
@@ -0,0 +1,20 @@
+package fixmultilinebad
+
+//vinego:check allfields
+type MyStruct struct { // want MyStruct:".*"
+	X int
+	Y string
+	Z *MyStruct
+}
+
+func consume(x []MyStruct) {}
+
+func main() {
+	consume([]MyStruct{
+		{ // want "Missing required"
+			X: 4,
+		},
+		{ // want "Missing required"
+		},
+	})
+}
@@ -0,0 +1,12 @@
+package dep
+
+type hidden struct {
+	A int
+}
+
+//vinego:check allfields
+type Conf struct { // want Conf:".*"
+	Name  string
+	Pairs [2]hidden
+	Ptr   *hidden
+}
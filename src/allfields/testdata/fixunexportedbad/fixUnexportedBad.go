@@ -0,0 +1,14 @@
+package fixunexportedbad
+
+import "fixunexportedbad/dep"
+
+//vinego:check allfields
+type Local struct { // want Local:".*"
+	X int
+}
+
+func main() {
+	// `[2]dep.hidden{}` can't be written here, so there's no fix
+	_ = dep.Conf{Name: "a"} // want "Missing required fields in struct literal: \\[Pairs Ptr\\]"
+	_ = Local{}             // want "Missing required fields in struct literal: \\[X\\]"
+}
@@ -0,0 +1,22 @@
+package genericbad
+
+//vinego:check allfields
+type Box[T any] struct { // want Box:".*"
+	Value T
+	Label string
+}
+
+type IntBox = Box[int]
+
+type Boxes[T any] = []Box[T]
+
+func consume(x any) {}
+
+func main() {
+	consume(Box[int]{Value: 4})       // want "Missing required fields in struct literal: \\[Label\\]"
+	consume(&Box[string]{Label: "x"}) // want "Missing required fields in struct literal: \\[Value\\]"
+	consume(IntBox{})                 // want "Missing required fields in struct literal: \\[Value Label\\]"
+	consume(Boxes[bool]{
+		{Value: true}, // want "Missing required fields in struct literal: \\[Label\\]"
+	})
+}
@@ -0,0 +1,17 @@
+package genericok
+
+//vinego:check allfields
+type Box[T any] struct { // want Box:".*"
+	Value T
+	Label string
+}
+
+type IntBox = Box[int]
+
+func consume(x any) {}
+
+func main() {
+	consume(Box[int]{Value: 4, Label: "x"})
+	consume(&Box[string]{Value: "y", Label: "x"})
+	consume(IntBox{Value: 1, Label: "z"})
+}
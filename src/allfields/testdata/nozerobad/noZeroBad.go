@@ -0,0 +1,67 @@
+package nozerobad
+
+import (
+	"errors"
+)
+
+//vinego:check nozero
+type Client struct { // want Client:".*"
+	addr string
+}
+
+func NewClient(addr string) (*Client, error) {
+	if addr == "" {
+		return nil, errors.New("no address")
+	}
+	return &Client{addr: addr}, nil
+}
+
+var global Client // want "Zero value construction of Client"
+
+func consume(x any) {}
+
+func (c *Client) Reset() {
+	*c = Client{} // want "Zero value construction of Client"
+}
+
+func load() (Client, error) {
+	c, err := NewClient("x")
+	if err != nil {
+		return *new(Client), err // want "Zero value construction of Client"
+	}
+	return *c, nil
+}
+
+// A bare return hands out the zero valued named result
+func parse(addr string) (c Client, err error) { // want "Zero value construction of Client"
+	if addr == "" {
+		err = errors.New("no address")
+		return
+	}
+	c.addr = addr
+	return
+}
+
+// Not constructors despite the prefix
+func Newsletter() *Client {
+	return new(Client) // want "Zero value construction of Client"
+}
+
+func newest() Client {
+	var c Client // want "Zero value construction of Client"
+	return c
+}
+
+// Doesn't return the type
+func NewCount() int {
+	consume(Client{}) // want "Zero value construction of Client"
+	return 0
+}
+
+func main() {
+	var c Client // want "Zero value construction of Client"
+	consume(c)
+	consume(new(Client))  // want "Zero value construction of Client"
+	consume(&Client{})    // want "Zero value construction of Client"
+	consume([]Client{{}}) // want "Zero value construction of Client"
+}
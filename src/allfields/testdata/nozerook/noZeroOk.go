@@ -0,0 +1,38 @@
+package nozerook
+
+//vinego:check nozero
+type Client struct { // want Client:".*"
+	addr string
+}
+
+func NewClient(addr string) *Client {
+	c := new(Client)
+	c.addr = addr
+	return c
+}
+
+func newDefaultClient() Client {
+	var c Client
+	c.addr = "localhost"
+	return c
+}
+
+func newParsed(addr string) (c Client, err error) {
+	c.addr = addr
+	return
+}
+
+func New() *Client {
+	return &Client{}
+}
+
+func consume(x any) {}
+
+func main() {
+	c := NewClient("x")
+	var d Client = newDefaultClient()
+	consume(c)
+	consume(d)
+	consume(new(d))
+	consume(Client{addr: "y"})
+}
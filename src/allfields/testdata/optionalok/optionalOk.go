@@ -1,6 +1,6 @@
 package optionaok
 
-// check:allfields
+//vinego:check allfields
 type MyStruct struct { // want MyStruct:".*"
 	X int `optional:"1"`
 }
@@ -0,0 +1,26 @@
+//vinego:check allfields // want package:"checks\\(allfields\\)"
+package packagebad
+
+type Exported struct {
+	X int
+}
+
+type unexported struct {
+	X int
+}
+
+type List []int
+
+//vinego:check allfields except=X
+type Overridden struct { // want Overridden:"checks\\(allfields\\)"
+	X int
+}
+
+func consume(x ...any) {}
+
+func main() {
+	consume(Exported{})   // want "Missing required fields in struct literal: \\[X\\]"
+	consume(unexported{}) // unexported types aren't covered by the package directive
+	consume(List{1, 2})
+	consume(Overridden{})
+}
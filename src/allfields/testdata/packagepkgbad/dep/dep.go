@@ -0,0 +1,7 @@
+//vinego:check allfields
+package dep
+
+type Conf struct {
+	Name string
+	Port int
+}
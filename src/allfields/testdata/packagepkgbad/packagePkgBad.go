@@ -0,0 +1,11 @@
+package packagepkgbad
+
+import (
+	"packagepkgbad/dep"
+)
+
+func consume(x dep.Conf) {}
+
+func main() {
+	consume(dep.Conf{Name: "x"}) // want "Missing required fields in struct literal: \\[Port\\]"
+}
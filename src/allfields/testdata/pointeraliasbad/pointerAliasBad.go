@@ -0,0 +1,22 @@
+package pointeraliasbad
+
+//vinego:check allfields
+type MyStruct struct { // want MyStruct:".*"
+	X int
+}
+
+type Alias = MyStruct
+
+type PtrAlias = *Alias
+
+func consume(x any) {}
+
+func main() {
+	consume(&Alias{}) // want "Missing required"
+	consume([]*Alias{
+		{}, // want "Missing required"
+	})
+	consume([]PtrAlias{
+		{}, // want "Missing required"
+	})
+}
@@ -0,0 +1,16 @@
+package positionalok
+
+//vinego:check allfields
+type MyStruct struct { // want MyStruct:".*"
+	X int
+	Y string
+}
+
+func consume(x []MyStruct) {}
+
+func main() {
+	consume([]MyStruct{
+		{4, "x"},
+		MyStruct{5, "y"},
+	})
+}
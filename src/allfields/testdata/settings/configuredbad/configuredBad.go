@@ -0,0 +1,12 @@
+package configuredbad
+
+import (
+	"image"
+)
+
+func consume(x ...any) {}
+
+func main() {
+	consume(image.Point{X: 4}) // want "Missing required fields in struct literal: \\[Y\\]"
+	consume(image.Rectangle{}) // want "Missing required fields in struct literal: \\[Min\\]"
+}
@@ -0,0 +1,22 @@
+package configuredok
+
+import (
+	"image"
+	"time"
+)
+
+//vinego:check allfields-deep
+type Window struct { // want Window:".*"
+	Bounds image.Rectangle
+}
+
+func consume(x ...any) {}
+
+func main() {
+	consume(image.Point{X: 4, Y: 2})
+	consume(image.Point{4, 2})
+	consume(image.Rectangle{Min: image.Point{X: 1, Y: 2}})
+	consume(Window{Bounds: image.Rectangle{Min: image.Point{X: 1, Y: 2}}})
+	// Unexported fields of other packages' types can't be set
+	consume(&time.Timer{C: make(chan time.Time)})
+}
@@ -0,0 +1,6 @@
+package dep
+
+type Conf struct {
+	Name string
+	Port int
+}
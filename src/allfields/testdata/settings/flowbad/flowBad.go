@@ -0,0 +1,42 @@
+package flowbad
+
+//vinego:check allfields
+type Config struct { // want Config:".*"
+	Host  string
+	Port  int
+	Debug bool `optional:"1"`
+}
+
+func produce() bool { return true }
+
+func consume(c Config) {}
+
+func partial() {
+	var cfg Config
+	cfg.Host = "h"
+	consume(cfg) // want "Missing required fields in `cfg` when used: \\[Port\\]"
+}
+
+func branch() {
+	var cfg Config
+	cfg.Host = "h"
+	if produce() {
+		cfg.Port = 1
+	}
+	consume(cfg) // want "`Port` isn't assigned in the following branches"
+}
+
+func method() string {
+	var cfg Config
+	cfg.Port = 1
+	return cfg.String() // want "Missing required fields in `cfg` when used: \\[Host\\]"
+}
+
+func (c Config) String() string {
+	return c.Host
+}
+
+func namedReturn() (cfg Config) { // want "Missing required fields in `cfg` when used: \\[Host\\]"
+	cfg.Port = 3
+	return
+}
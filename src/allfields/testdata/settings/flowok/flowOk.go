@@ -0,0 +1,60 @@
+package flowok
+
+import (
+	"encoding/json"
+)
+
+//vinego:check allfields
+type Config struct { // want Config:".*"
+	Host  string
+	Port  int
+	Debug bool `optional:"1"`
+}
+
+func produce() bool { return true }
+
+func consume(c Config) {}
+
+func assigned() {
+	var cfg Config
+	cfg.Host = "h"
+	if produce() {
+		cfg.Port = 1
+	} else {
+		cfg.Port = 2
+	}
+	consume(cfg)
+}
+
+func fieldRead() int {
+	var cfg Config
+	cfg.Port = 1
+	port := cfg.Port
+	cfg.Host = "h"
+	consume(cfg)
+	return port
+}
+
+func unmarshal(data []byte) {
+	var cfg Config
+	_ = json.Unmarshal(data, &cfg)
+	consume(cfg)
+}
+
+func namedReturn() (cfg Config) {
+	cfg.Host = "h"
+	cfg.Port = 3
+	return
+}
+
+//vinego:check initializer
+func (c *Config) Defaults() { // want Defaults:"checks\\(initializer\\)"
+	c.Host = "localhost"
+	c.Port = 80
+}
+
+func initializer() {
+	var cfg Config
+	cfg.Defaults()
+	consume(cfg)
+}
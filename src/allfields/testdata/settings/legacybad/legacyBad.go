@@ -0,0 +1,12 @@
+package legacybad
+
+// check:allfields
+type MyStruct struct { // want MyStruct:".*"
+	X int
+}
+
+func consume(x MyStruct) {}
+
+func main() {
+	consume(MyStruct{}) // want "Missing required"
+}
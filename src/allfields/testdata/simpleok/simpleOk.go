@@ -1,6 +1,6 @@
 package simpleok
 
-// check:allfields
+//vinego:check allfields
 type MyStruct struct { // want MyStruct:".*"
 	X int
 }
@@ -17,7 +17,7 @@ func New() *analysis.Analyzer {
 		Doc:  "_",
 		Run: func(p *analysis.Pass) (any, error) {
 			for _, file := range p.Files {
-				// check:allfields
+				//vinego:check allfields
 				type Layer struct {
 					Declarations []token.Pos
 				}
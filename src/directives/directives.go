@@ -0,0 +1,232 @@
+package directives
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/upsun/vinego/src/utils"
+)
+
+//vinego:check allfields
+type Settings struct {
+	// Also validate legacy `check:name` tags, and don't suggest migrating them
+	LegacyDirectives bool `json:"-"`
+}
+
+// Edit distance, for suggesting corrections
+func distance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func unknownCheckMessage(name string) string {
+	known := []string{}
+	for k := range utils.KnownChecks {
+		utils.Append(&known, k)
+	}
+	slices.Sort(known)
+	for _, k := range known {
+		if distance(name, k) <= 2 {
+			return fmt.Sprintf("Unknown check `%s`, did you mean `%s`?", name, k)
+		}
+	}
+	return fmt.Sprintf("Unknown check `%s`, known checks are %v", name, known)
+}
+
+// Where a doc comment is attached
+//
+//vinego:check allfields
+type docTarget struct {
+	Placement utils.Placement
+	// The declarations the comment's checks apply to.  A type declaration group's comment applies to
+	// each of its types.
+	Decls []ast.Node
+}
+
+// Returns where each doc comment in the file is attached
+func docPlacements(file *ast.File) map[*ast.CommentGroup]docTarget {
+	out := map[*ast.CommentGroup]docTarget{}
+	set := func(group *ast.CommentGroup, placement utils.Placement, decls ...ast.Node) {
+		if group != nil {
+			out[group] = docTarget{Placement: placement, Decls: decls}
+		}
+	}
+	set(file.Doc, utils.PlacementPackage, file)
+	ast.Inspect(file, func(n0 ast.Node) bool {
+		switch n := n0.(type) {
+		case *ast.GenDecl:
+			specs := []ast.Node{}
+			for _, spec := range n.Specs {
+				utils.Append(&specs, ast.Node(spec))
+			}
+			switch n.Tok {
+			case token.IMPORT:
+				set(n.Doc, utils.PlacementImport, specs...)
+			case token.TYPE:
+				set(n.Doc, utils.PlacementType, specs...)
+			default:
+				set(n.Doc, utils.PlacementVar, specs...)
+			}
+		case *ast.TypeSpec:
+			set(n.Doc, utils.PlacementType, n)
+		case *ast.ValueSpec:
+			set(n.Doc, utils.PlacementVar, n)
+		case *ast.FuncDecl:
+			set(n.Doc, utils.FuncPlacement(n), n)
+		case *ast.StructType:
+			for _, field := range n.Fields.List {
+				set(field.Doc, utils.PlacementField, field)
+			}
+		case *ast.InterfaceType:
+			for _, method := range n.Methods.List {
+				if len(method.Names) > 0 {
+					set(method.Doc, utils.PlacementMethod, method)
+				}
+			}
+		}
+		return true
+	})
+	return out
+}
+
+func New(settings Settings) *analysis.Analyzer {
+	return &analysis.Analyzer{
+		Name: "directives",
+		Doc:  "_",
+		Run: func(p *analysis.Pass) (any, error) {
+			for _, file := range p.Files {
+				placements := docPlacements(file)
+				// Checks seen on each declaration, across its comments
+				seen := map[ast.Node]map[string]bool{}
+				for _, group := range file.Comments {
+					target, attached := placements[group]
+					placement := target.Placement
+					for _, c := range group.List {
+						directive, err := utils.ParseDirective(c)
+						if err != nil {
+							p.Report(analysis.Diagnostic{
+								Pos:     c.Pos(),
+								Message: fmt.Sprintf("Malformed directive: %s", err),
+							})
+							continue
+						}
+						if directive == nil {
+							if !settings.LegacyDirectives && attached {
+								// Older versions enabled known checks mentioned anywhere in the comment
+								for _, matches := range utils.LegacyCheckRegexp.FindAllStringSubmatch(utils.StripTrailingComment(c.Text), -1) {
+									if _, known := utils.KnownChecks[matches[1]]; !known {
+										continue
+									}
+									p.Report(analysis.Diagnostic{
+										Pos: c.Pos(),
+										Message: fmt.Sprintf(
+											"Legacy tag `check:%s` is ignored, use `%scheck %s` or enable legacy_directives",
+											matches[1],
+											utils.DirectivePrefix,
+											matches[1],
+										),
+									})
+								}
+							}
+							continue
+						}
+						if !utils.KnownDirectives[directive.Verb] {
+							p.Report(analysis.Diagnostic{
+								Pos:     c.Pos(),
+								Message: fmt.Sprintf("Unknown directive `%s%s`", utils.DirectivePrefix, directive.Verb),
+							})
+							continue
+						}
+						if len(directive.Names) != 1 {
+							p.Report(analysis.Diagnostic{
+								Pos:     c.Pos(),
+								Message: fmt.Sprintf("`%scheck` takes exactly one check name", utils.DirectivePrefix),
+							})
+							continue
+						}
+						name := directive.Names[0]
+						spec, known := utils.KnownChecks[name]
+						if !known {
+							p.Report(analysis.Diagnostic{
+								Pos:     c.Pos(),
+								Message: unknownCheckMessage(name),
+							})
+							continue
+						}
+						if !attached {
+							p.Report(analysis.Diagnostic{
+								Pos:     c.Pos(),
+								Message: fmt.Sprintf("Check `%s` isn't attached to a declaration", name),
+							})
+						} else if !spec.Allows(placement) {
+							p.Report(analysis.Diagnostic{
+								Pos: c.Pos(),
+								Message: fmt.Sprintf(
+									"Check `%s` can't be placed on a %s declaration, only %v",
+									name,
+									placement,
+									spec.Placements,
+								),
+							})
+						}
+						for key := range directive.Args {
+							if !slices.Contains(spec.Args, key) {
+								p.Report(analysis.Diagnostic{
+									Pos:     c.Pos(),
+									Message: fmt.Sprintf("Check `%s` doesn't accept argument `%s`", name, key),
+								})
+							}
+						}
+						duplicate := false
+						for _, decl := range target.Decls {
+							if seen[decl] == nil {
+								seen[decl] = map[string]bool{}
+							}
+							duplicate = duplicate || seen[decl][name]
+							seen[decl][name] = true
+						}
+						if duplicate {
+							p.Report(analysis.Diagnostic{
+								Pos:     c.Pos(),
+								Message: fmt.Sprintf("Duplicate check `%s`", name),
+							})
+						}
+					}
+					if settings.LegacyDirectives && placement == utils.PlacementType {
+						for line := range strings.SplitSeq(group.Text(), "\n") {
+							for _, matches := range utils.LegacyCheckRegexp.FindAllStringSubmatch(line, -1) {
+								if _, known := utils.KnownChecks[matches[1]]; !known {
+									p.Report(analysis.Diagnostic{
+										Pos:     group.Pos(),
+										Message: unknownCheckMessage(matches[1]),
+									})
+								}
+							}
+						}
+					}
+				}
+			}
+			return nil, nil
+		},
+	}
+}
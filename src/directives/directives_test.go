@@ -0,0 +1,15 @@
+package directives
+
+import (
+	"testing"
+
+	"github.com/upsun/vinego/src/testutils"
+)
+
+func TestAnalyzers(t *testing.T) {
+	testutils.RunTests(t, New(Settings{LegacyDirectives: false}), nil)
+}
+
+func TestLegacy(t *testing.T) {
+	testutils.RunTestsIn(t, "testdata/legacy", New(Settings{LegacyDirectives: true}), nil)
+}
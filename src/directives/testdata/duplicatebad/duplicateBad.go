@@ -0,0 +1,18 @@
+package duplicatebad
+
+// want +3 "Duplicate check `allfields`"
+//
+//vinego:check allfields
+//vinego:check allfields except=X
+type A struct {
+	X int
+}
+
+//vinego:check allfields
+type (
+	// want +1 "Duplicate check `allfields`"
+	//vinego:check allfields
+	B struct {
+		X int
+	}
+)
@@ -0,0 +1,9 @@
+package legacyok
+
+// check:allfields
+type A struct {
+	X int
+}
+
+//vinego:check nozero
+type B struct{}
@@ -0,0 +1,6 @@
+package legacyunknownbad
+
+// check:alfields // want "Unknown check `alfields`, did you mean `allfields`\\?"
+type A struct {
+	X int
+}
@@ -0,0 +1,14 @@
+package legacyhintbad
+
+// check:allfields // want "Legacy tag `check:allfields` is ignored, use `//vinego:check allfields` or enable legacy_directives"
+type A struct {
+	X int
+}
+
+// Older versions also enabled checks mentioned in prose, like check:allfields here // want "Legacy tag `check:allfields` is ignored"
+type B struct {
+	X int
+}
+
+// check:everything isn't a known check
+type C struct{}
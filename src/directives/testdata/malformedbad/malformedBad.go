@@ -0,0 +1,20 @@
+package malformedbad
+
+// want +1 "Malformed directive: missing or invalid directive name"
+//vinego:
+type A struct{}
+
+// want +2 "Malformed directive: empty value in `except=`"
+//
+//vinego:check allfields except=
+type B struct{}
+
+// want +2 "`//vinego:check` takes exactly one check name"
+//
+//vinego:check allfields nozero
+type C struct{}
+
+// want +2 "Check `nozero` doesn't accept argument `except`"
+//
+//vinego:check nozero except=X
+type D struct{}
@@ -0,0 +1,29 @@
+package misplacedbad
+
+// want +2 "Check `allfields` can't be placed on a func declaration, only \\[type package\\]"
+//
+//vinego:check allfields
+func F() {}
+
+// want +2 "Check `nozero` can't be placed on a var declaration"
+//
+//vinego:check nozero
+var V int
+
+type S struct {
+	// want +1 "Check `allfields` can't be placed on a field declaration"
+	//vinego:check allfields
+	X int
+}
+
+// Value receiver methods can't initialize their receiver
+// want +2 "Check `initializer` can't be placed on a method declaration, only \\[pointer receiver method\\]"
+//
+//vinego:check initializer
+func (s S) Init() {}
+
+func G() {
+	// want +1 "Check `allfields` isn't attached to a declaration"
+	//vinego:check allfields
+	_ = 4
+}
@@ -0,0 +1,30 @@
+package ok
+
+//vinego:check allfields
+type A struct {
+	X int
+}
+
+//vinego:check allfields except=Y,Z
+//vinego:check nozero
+type B struct {
+	X int
+	Y int
+	Z int
+}
+
+type (
+	//vinego:check allfields-deep
+	C struct {
+		B B
+	}
+)
+
+// Prose mentioning check:everything isn't a directive, since it's not a known check
+type D struct{}
+
+//vinego:check initializer
+func (d *D) Init() {}
+
+//vinego:check noreturn
+func (d D) Fail() {}
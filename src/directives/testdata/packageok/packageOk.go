@@ -0,0 +1,14 @@
+// Package docs
+//
+//vinego:check allfields
+package packageok
+
+type A struct {
+	X int
+}
+
+type I interface {
+	// want +1 "Check `nozero` can't be placed on a method declaration"
+	//vinego:check nozero
+	M()
+}
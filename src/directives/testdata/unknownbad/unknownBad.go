@@ -0,0 +1,18 @@
+package unknownbad
+
+// want +2 "Unknown check `alfields`, did you mean `allfields`\\?"
+//
+//vinego:check alfields
+type A struct {
+	X int
+}
+
+// want +2 "Unknown check `everything`, known checks are"
+//
+//vinego:check everything
+type B struct{}
+
+// want +2 "Unknown directive `//vinego:chek`"
+//
+//vinego:chek allfields
+type C struct{}
@@ -5,6 +5,7 @@ import (
 	"go/ast"
 	"go/token"
 	"go/types"
+	"regexp"
 	"strings"
 
 	"golang.org/x/tools/go/analysis"
@@ -12,68 +13,235 @@ import (
 	"github.com/upsun/vinego/src/utils"
 )
 
-func wantSet(names ...string) map[string]bool {
-	keys := append(names, "interface{}", "any")
-	out := map[string]bool{}
-	for _, k := range keys {
-		out[k] = true
-		// TODO should get el if variadic rather than this hack
-		out["[]"+k] = true
+// Whether an untyped constant can be converted to the type without naming the type: the predeclared
+// types (including `byte` and `rune`), and interfaces where the constant takes its default type.
+// Named types need an explicit conversion.  A type parameter needs one unless every type in its
+// type set is predeclared (no `~int` terms), since otherwise it can be instantiated with a named
+// type.
+func acceptsConstant(t types.Type) bool {
+	switch t := types.Unalias(t).(type) {
+	case *types.Basic:
+		return true
+	case *types.Named:
+		return types.IsInterface(t)
+	case *types.TypeParam:
+		return predeclaredTypeSet(t.Constraint().Underlying().(*types.Interface))
 	}
-	return out
+	// Composite types only take the untyped nil, which isn't checked
+	return true
 }
 
-func New() *analysis.Analyzer {
-	wantString := wantSet("string")
-	wantChar := wantSet("char", "rune", "byte")
-	wantInts := wantSet(
-		"int",
-		"int8",
-		"int16",
-		"int32",
-		"int64",
-		"uint",
-		"uint8",
-		"uint16",
-		"uint32",
-		"uint64",
-	)
-	wantFloats := wantSet(
-		"float",
-		"float32",
-		"float64",
-	)
+func predeclaredTypeSet(iface *types.Interface) bool {
+	for i := 0; i < iface.NumEmbeddeds(); i++ {
+		switch embedded := types.Unalias(iface.EmbeddedType(i)).(type) {
+		case *types.Basic:
+		case *types.Union:
+			for j := 0; j < embedded.Len(); j++ {
+				term := embedded.Term(j)
+				if _, isBasic := types.Unalias(term.Type()).(*types.Basic); term.Tilde() || !isBasic {
+					return false
+				}
+			}
+		default:
+			nested, isInterface := embedded.Underlying().(*types.Interface)
+			if !isInterface || !predeclaredTypeSet(nested) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Returns the kind (`types.UntypedInt`, ...) of an untyped numeric, rune or string constant
+// expression, like `5`, `2*60`, `(5)` or a constant declared without a type.  Expressions with typed
+// constants like `2*time.Second` are typed.  The type checker records the type such expressions are
+// converted to rather than this.
+func untypedKind(p *analysis.Pass, e ast.Expr) (types.BasicKind, bool) {
+	switch e := e.(type) {
+	case *ast.BasicLit:
+		switch e.Kind {
+		case token.INT:
+			return types.UntypedInt, true
+		case token.FLOAT:
+			return types.UntypedFloat, true
+		case token.IMAG:
+			return types.UntypedComplex, true
+		case token.CHAR:
+			return types.UntypedRune, true
+		case token.STRING:
+			return types.UntypedString, true
+		}
+	case *ast.ParenExpr:
+		return untypedKind(p, e.X)
+	case *ast.UnaryExpr:
+		return untypedKind(p, e.X)
+	case *ast.BinaryExpr:
+		switch e.Op {
+		case token.SHL, token.SHR:
+			return untypedKind(p, e.X)
+		case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ:
+			// Comparisons are untyped booleans
+			return types.Invalid, false
+		}
+		x, xUntyped := untypedKind(p, e.X)
+		y, yUntyped := untypedKind(p, e.Y)
+		if !xUntyped || !yUntyped {
+			return types.Invalid, false
+		}
+		// Mixed constants take the later kind of int, rune, float and complex
+		return max(x, y), true
+	case *ast.Ident:
+		return untypedConstKind(p, e)
+	case *ast.SelectorExpr:
+		return untypedConstKind(p, e.Sel)
+	}
+	return types.Invalid, false
+}
+
+func untypedConstKind(p *analysis.Pass, ident *ast.Ident) (types.BasicKind, bool) {
+	constant, isConst := p.TypesInfo.Uses[ident].(*types.Const)
+	if !isConst || constant.Parent() == types.Universe {
+		// `iota`, `true` and `false`
+		return types.Invalid, false
+	}
+	basic, isBasic := constant.Type().(*types.Basic)
+	if !isBasic || basic.Info()&types.IsUntyped == 0 || basic.Info()&types.IsBoolean != 0 {
+		return types.Invalid, false
+	}
+	return basic.Kind(), true
+}
+
+// Returns the expression as written
+func sourceText(p *analysis.Pass, e ast.Expr) string {
+	tokFile := p.Fset.File(e.Pos())
+	if tokFile != nil {
+		if source, err := p.ReadFile(tokFile.Name()); err == nil {
+			return string(source[tokFile.Offset(e.Pos()):tokFile.Offset(e.End())])
+		}
+	}
+	return types.ExprString(e)
+}
+
+//vinego:check allfields
+type Settings struct {
+	// Only check conversions to types marked with `//vinego:check explicitcast` and denied types,
+	// instead of all named types
+	OptIn bool `json:"opt_in" optional:"1"`
+	// Patterns of types that constants may be implicitly converted to, qualified like
+	// `io/fs.FileMode`.  `*` matches any characters, ex: `example.com/flags.*`.
+	Allow []string `json:"allow" optional:"1"`
+	// Patterns of types that are always checked, even if they match Allow or aren't marked in opt-in
+	// mode
+	Deny []string `json:"deny" optional:"1"`
+	// Recognize legacy `check:name` tags in doc comments, set from the top level setting
+	LegacyDirectives bool `json:"-" optional:"1"`
+}
+
+// Compiles type patterns into a single expression, nil if there are none
+func typePatterns(patterns []string) *regexp.Regexp {
+	if len(patterns) == 0 {
+		return nil
+	}
+	alternatives := []string{}
+	for _, pattern := range patterns {
+		utils.Append(&alternatives, strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*"))
+	}
+	return regexp.MustCompile("^(?:" + strings.Join(alternatives, "|") + ")$")
+}
+
+// Whether conversions to the named type (or type parameter) are checked.  Aliases are matched and
+// can be marked under their own name as well as the type's.
+func checksType(p *analysis.Pass, settings Settings, allow *regexp.Regexp, deny *regexp.Regexp, t types.Type) bool {
+	objs := []*types.TypeName{}
+	for {
+		alias, isAlias := t.(*types.Alias)
+		if !isAlias {
+			break
+		}
+		utils.Append(&objs, alias.Obj())
+		t = alias.Rhs()
+	}
+	if named, isNamed := types.Unalias(t).(*types.Named); isNamed {
+		utils.Append(&objs, named.Origin().Obj())
+	}
+	matches := func(patterns *regexp.Regexp) bool {
+		for _, obj := range objs {
+			if patterns != nil && patterns.MatchString(utils.QualifiedName(obj)) {
+				return true
+			}
+		}
+		return false
+	}
+	if matches(deny) {
+		return true
+	}
+	if matches(allow) {
+		return false
+	}
+	for _, obj := range objs {
+		if utils.GetTags(p, obj).Has("explicitcast") {
+			return true
+		}
+	}
+	return !settings.OptIn
+}
+
+func New(settings Settings) *analysis.Analyzer {
+	allow := typePatterns(settings.Allow)
+	deny := typePatterns(settings.Deny)
 	return &analysis.Analyzer{
-		Name: "explicitcast",
-		Doc:  "_",
+		Name:      "explicitcast",
+		Doc:       "_",
+		FactTypes: []analysis.Fact{new(utils.ChecksFact)},
 		Run: func(p *analysis.Pass) (any, error) {
-			checkLit := func(p *analysis.Pass, t types.Type, e ast.Expr) {
-				basicLit, isBasicLit := e.(*ast.BasicLit)
-				if !isBasicLit {
+			utils.ScanTags(p, settings.LegacyDirectives)
+			checkLit := func(p *analysis.Pass, file *ast.File, t types.Type, e ast.Expr) {
+				if t == nil || p.TypesInfo.Types[e].Value == nil {
 					return
 				}
-				var want map[string]bool
-				switch basicLit.Kind {
-				case token.STRING:
-					want = wantString
-				case token.CHAR:
-					want = wantChar
-				case token.INT:
-					want = wantInts
-				case token.FLOAT, token.IMAG:
-					want = wantFloats
-				default:
-					panic("ASSERTION! Unexpected literal kind")
+				kind, untyped := untypedKind(p, e)
+				if !untyped {
+					return
 				}
-				if want[t.String()] {
+				if acceptsConstant(t) || !checksType(p, settings, allow, deny, t) {
 					return
 				}
+				var kindName string
+				switch kind {
+				case types.UntypedString:
+					kindName = token.STRING.String()
+				case types.UntypedRune:
+					kindName = token.CHAR.String()
+				case types.UntypedInt:
+					kindName = token.INT.String()
+				case types.UntypedFloat:
+					kindName = token.FLOAT.String()
+				case types.UntypedComplex:
+					kindName = token.IMAG.String()
+				default:
+					panic("ASSERTION! Unexpected constant kind")
+				}
 				p.Report(analysis.Diagnostic{
-					Pos:     e.Pos(),
-					Message: fmt.Sprintf("Implicit literal cast of %s to %s", basicLit.Kind.String(), t.String()),
+					Pos:            e.Pos(),
+					Message:        fmt.Sprintf("Implicit literal cast of %s to %s: `%s`", kindName, t.String(), sourceText(p, e)),
+					SuggestedFixes: conversionFixes(p, file, t, e),
 				})
 			}
 
+			// Elements of slice, array and map literals, with keys for maps
+			checkElements := func(p *analysis.Pass, file *ast.File, lit *ast.CompositeLit, keyType types.Type, elemType types.Type) {
+				for _, elt := range lit.Elts {
+					if kv, isKv := elt.(*ast.KeyValueExpr); isKv {
+						if keyType != nil {
+							checkLit(p, file, keyType, kv.Key)
+						}
+						checkLit(p, file, elemType, kv.Value)
+					} else {
+						checkLit(p, file, elemType, elt)
+					}
+				}
+			}
+
 		NextFile:
 			for _, file := range p.Files {
 				for _, excl := range []string{
@@ -92,7 +260,7 @@ func New() *analysis.Analyzer {
 							// like `(func())(nil)` -- TypesInfo.TypeOf(fun) returns Sig same as a func obj, need to differentiate this way
 							break
 						}
-						switch funType := funTypeObj.Type.(type) {
+						switch funType := funTypeObj.Type.Underlying().(type) {
 						case *types.Signature:
 							if funType.Params().Len() > 1 && len(n.Args) == 1 {
 								// function call multi-return forwarding - no implicit casts here
@@ -101,11 +269,15 @@ func New() *analysis.Analyzer {
 							for i, arg := range n.Args {
 								var argType types.Type
 								if funType.Variadic() && i >= funType.Params().Len()-1 {
+									// Passed as elements of the variadic slice, unless spread with `...`
 									argType = funType.Params().At(funType.Params().Len() - 1).Type()
+									if !n.Ellipsis.IsValid() {
+										argType = argType.Underlying().(*types.Slice).Elem()
+									}
 								} else {
 									argType = funType.Params().At(i).Type()
 								}
-								checkLit(p, argType, arg)
+								checkLit(p, file, argType, arg)
 							}
 						case types.Type:
 							// nop - ok
@@ -119,8 +291,66 @@ func New() *analysis.Analyzer {
 							source := n.Rhs[i]
 							destType := p.TypesInfo.TypeOf(dest)
 							if destType != nil {
-								checkLit(p, destType, source)
+								checkLit(p, file, destType, source)
+							}
+						}
+					case *ast.ValueSpec:
+						if n.Type == nil {
+							// Takes the type of the values
+							break
+						}
+						if decl, isDecl := utils.Last(crumbs).(*ast.GenDecl); isDecl && decl.Tok == token.CONST {
+							// Declaring a typed constant is the explicit way to write one
+							break
+						}
+						for _, value := range n.Values {
+							checkLit(p, file, p.TypesInfo.TypeOf(n.Type), value)
+						}
+					case *ast.CompositeLit:
+						litType := p.TypesInfo.TypeOf(n)
+						if litType == nil {
+							break
+						}
+						switch t := litType.Underlying().(type) {
+						case *types.Struct:
+							for i, elt := range n.Elts {
+								if kv, isKv := elt.(*ast.KeyValueExpr); isKv {
+									checkLit(p, file, p.TypesInfo.TypeOf(kv.Key), kv.Value)
+								} else if i < t.NumFields() {
+									checkLit(p, file, t.Field(i).Type(), elt)
+								}
 							}
+						case *types.Slice:
+							checkElements(p, file, n, nil, t.Elem())
+						case *types.Array:
+							checkElements(p, file, n, nil, t.Elem())
+						case *types.Map:
+							checkElements(p, file, n, t.Key(), t.Elem())
+						}
+					case *ast.SendStmt:
+						if chanType, isChan := p.TypesInfo.TypeOf(n.Chan).Underlying().(*types.Chan); isChan {
+							checkLit(p, file, chanType.Elem(), n.Value)
+						}
+					case *ast.BinaryExpr:
+						switch n.Op {
+						case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ:
+							checkLit(p, file, p.TypesInfo.TypeOf(n.X), n.Y)
+							checkLit(p, file, p.TypesInfo.TypeOf(n.Y), n.X)
+						}
+					case *ast.SwitchStmt:
+						if n.Tag == nil {
+							// Conditions are checked as comparisons
+							break
+						}
+						tagType := p.TypesInfo.TypeOf(n.Tag)
+						for _, clause := range n.Body.List {
+							for _, value := range clause.(*ast.CaseClause).List {
+								checkLit(p, file, tagType, value)
+							}
+						}
+					case *ast.IndexExpr:
+						if mapType, isMap := p.TypesInfo.TypeOf(n.X).Underlying().(*types.Map); isMap {
+							checkLit(p, file, mapType.Key(), n.Index)
 						}
 					case *ast.ReturnStmt:
 						if len(n.Results) == 0 {
@@ -152,7 +382,7 @@ func New() *analysis.Analyzer {
 						}
 						for i := 0; i < inFunc.Results().Len(); i++ {
 							retType := inFunc.Results().At(i)
-							checkLit(p, retType.Type(), n.Results[i])
+							checkLit(p, file, retType.Type(), n.Results[i])
 						}
 					}
 					return true
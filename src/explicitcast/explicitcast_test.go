@@ -7,5 +7,20 @@ import (
 )
 
 func TestAnalyzers(t *testing.T) {
-	testutils.RunTests(t, New(), nil)
+	testutils.RunTests(t, New(Settings{}), nil)
+}
+
+func TestOptIn(t *testing.T) {
+	testutils.RunTestsIn(t, "testdata/settings/optin", New(Settings{
+		OptIn: true,
+		Allow: []string{"optinok.Allowed"},
+		Deny:  []string{"time.Duration", "optinbad/money.*"},
+	}), nil)
+}
+
+func TestExclusions(t *testing.T) {
+	testutils.RunTestsIn(t, "testdata/settings/exclusions", New(Settings{
+		Allow: []string{"io/fs.FileMode", "*/flags.*"},
+		Deny:  []string{"*/flags.Strict"},
+	}), nil)
 }
@@ -0,0 +1,90 @@
+package explicitcast
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"time"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/upsun/vinego/src/utils"
+)
+
+// Units offered for `time.Duration` constants, smallest first
+var durationUnits = []struct {
+	Name  string
+	Value time.Duration
+}{
+	{Name: "Nanosecond", Value: time.Nanosecond},
+	{Name: "Microsecond", Value: time.Microsecond},
+	{Name: "Millisecond", Value: time.Millisecond},
+	{Name: "Second", Value: time.Second},
+	{Name: "Minute", Value: time.Minute},
+	{Name: "Hour", Value: time.Hour},
+}
+
+// Returns fixes making the conversion of the constant expression explicit, as `T(4)` with the type
+// written as it's visible in the file.  For `time.Duration` the alternatives `4 * time.Second` etc.
+// are offered too, for the units that keep the value in range.  Returns no fixes if the type can't
+// be referred to from the file.
+func conversionFixes(p *analysis.Pass, file *ast.File, t types.Type, e ast.Expr) []analysis.SuggestedFix {
+	if named, isNamed := types.Unalias(t).(*types.Named); isNamed {
+		obj := named.Obj()
+		if obj.Pkg() != nil && obj.Pkg() != p.Pkg && !obj.Exported() {
+			return nil
+		}
+	}
+	unresolved := false
+	typeName := types.TypeString(t, utils.FileQualifier(p, file, &unresolved))
+	if unresolved {
+		return nil
+	}
+	source := sourceText(p, e)
+	converted := typeName + "(" + source + ")"
+	if _, isParen := e.(*ast.ParenExpr); isParen {
+		converted = typeName + source
+	}
+	out := []analysis.SuggestedFix{{
+		Message: "Convert explicitly to " + typeName,
+		TextEdits: []analysis.TextEdit{{
+			Pos:     e.Pos(),
+			End:     e.End(),
+			NewText: []byte(converted),
+		}},
+	}}
+
+	named, isNamed := types.Unalias(t).(*types.Named)
+	if !isNamed || utils.QualifiedName(named.Obj()) != "time.Duration" {
+		return out
+	}
+	timeName, imported := utils.ImportNames(p, file)["time"]
+	if !imported {
+		return out
+	}
+	unitPrefix := timeName + "."
+	if timeName == "." {
+		unitPrefix = ""
+	}
+	value := p.TypesInfo.Types[e].Value
+	multiplied := source
+	if _, isBinary := e.(*ast.BinaryExpr); isBinary {
+		multiplied = "(" + source + ")"
+	}
+	for _, unit := range durationUnits {
+		if _, exact := constant.Int64Val(constant.BinaryOp(value, token.MUL, constant.MakeInt64(int64(unit.Value)))); !exact {
+			// Overflows
+			continue
+		}
+		utils.Append(&out, analysis.SuggestedFix{
+			Message: "Multiply by " + unitPrefix + unit.Name,
+			TextEdits: []analysis.TextEdit{{
+				Pos:     e.Pos(),
+				End:     e.End(),
+				NewText: []byte(multiplied + " * " + unitPrefix + unit.Name),
+			}},
+		})
+	}
+	return out
+}
@@ -0,0 +1,17 @@
+package basickindsbad
+
+type Char byte
+
+type Ratio float64
+
+type Name string
+
+type Alias = Ratio
+
+func main() {
+	var c Char = 'a'  // want "Implicit literal cast of CHAR to basickindsbad.Char"
+	var r Ratio = 4   // want "Implicit literal cast of INT to basickindsbad.Ratio"
+	var n Name = "a"  // want "Implicit literal cast of STRING to basickindsbad.Name"
+	var a Alias = 0.5 // want "Implicit literal cast of FLOAT to basickindsbad.Alias"
+	_, _, _, _ = c, r, n, a
+}
@@ -0,0 +1,22 @@
+package basickindsok
+
+import "math"
+
+type Stringer interface {
+	String() string
+}
+
+type Any = any
+
+func main() {
+	var b byte = 'a'
+	var r rune = 'a'
+	var f float64 = 4
+	var c complex128 = 1
+	var u uint8 = 'a'
+	var s string = "a"
+	var a Any = 4
+	var i interface{} = 2.5
+	_ = math.Sqrt(4)
+	_, _, _, _, _, _, _, _ = b, r, f, c, u, s, a, i
+}
@@ -0,0 +1,16 @@
+package comparisonbad
+
+import "time"
+
+type Level int
+
+func main() {
+	var d time.Duration
+	if d > 10 { // want "Implicit literal cast of INT to time.Duration"
+		return
+	}
+	var l Level
+	if 3 == l { // want "Implicit literal cast of INT to comparisonbad.Level"
+		return
+	}
+}
@@ -0,0 +1,23 @@
+package comparisonok
+
+import "time"
+
+type Level int
+
+func main() {
+	var d time.Duration
+	if d > 10*time.Second {
+		return
+	}
+	var l Level
+	if Level(3) == l {
+		return
+	}
+	var i int
+	if i > 10 {
+		return
+	}
+	if 1 < 2 {
+		return
+	}
+}
@@ -0,0 +1,24 @@
+package compositelitbad
+
+import "time"
+
+type Port int
+
+type Level int
+
+type Config struct {
+	Timeout time.Duration
+	Port    Port
+	Name    string
+}
+
+func main() {
+	_ = Config{Timeout: 30, Name: "a"}         // want "Implicit literal cast of INT to time.Duration"
+	_ = Config{time.Second, 80, "a"}           // want "Implicit literal cast of INT to compositelitbad.Port"
+	_ = []Port{80, Port(443)}                  // want "Implicit literal cast of INT to compositelitbad.Port"
+	_ = [2]Port{0: 80, 1: Port(443)}           // want "Implicit literal cast of INT to compositelitbad.Port"
+	_ = map[Level]string{3: "x"}               // want "Implicit literal cast of INT to compositelitbad.Level"
+	_ = map[string]Level{"x": 3}               // want "Implicit literal cast of INT to compositelitbad.Level"
+	_ = []Config{{Port: 80}}                   // want "Implicit literal cast of INT to compositelitbad.Port"
+	_ = map[string][]Port{"x": {Port(80), 81}} // want "Implicit literal cast of INT to compositelitbad.Port"
+}
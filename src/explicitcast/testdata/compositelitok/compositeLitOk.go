@@ -0,0 +1,26 @@
+package compositelitok
+
+import "time"
+
+type Port int
+
+type Level int
+
+type Config struct {
+	Timeout time.Duration
+	Port    Port
+	Name    string
+	Retries int
+}
+
+func main() {
+	_ = Config{Timeout: 30 * time.Second, Name: "a", Retries: 3}
+	_ = Config{time.Second, Port(80), "a", 3}
+	_ = []Port{Port(80), Port(443)}
+	_ = [2]Port{0: Port(80), 1: Port(443)}
+	_ = map[Level]string{Level(3): "x"}
+	_ = map[string]Level{"x": Level(3)}
+	_ = []Config{{Port: Port(80)}}
+	_ = []int{1, 2, 3}
+	_ = []any{1, "a"}
+}
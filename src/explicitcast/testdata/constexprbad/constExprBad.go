@@ -0,0 +1,24 @@
+package constexprbad
+
+import "time"
+
+type Retries int
+
+const retries = 3
+
+const (
+	minutes = 60
+	factor  = 1.5
+)
+
+func retry(r Retries) {}
+
+func main() {
+	time.Sleep(-1)               // want "Implicit literal cast of INT to time.Duration: `-1`"
+	time.Sleep(2 * 60)           // want "Implicit literal cast of INT to time.Duration: `2 \\* 60`"
+	time.Sleep((5))              // want "Implicit literal cast of INT to time.Duration: `\\(5\\)`"
+	time.Sleep(minutes * factor) // want "Implicit literal cast of FLOAT to time.Duration: `minutes \\* factor`"
+	time.Sleep(1 << 3)           // want "Implicit literal cast of INT to time.Duration: `1 << 3`"
+	retry(retries)               // want "Implicit literal cast of INT to constexprbad.Retries: `retries`"
+	retry('a')                   // want "Implicit literal cast of CHAR to constexprbad.Retries: `'a'`"
+}
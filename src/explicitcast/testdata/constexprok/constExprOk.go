@@ -0,0 +1,48 @@
+package constexprok
+
+import "time"
+
+type Retries int
+
+const retries Retries = 3
+
+const timeout = 2 * time.Second
+
+type Level int
+
+const (
+	Low Level = iota
+	High
+)
+
+const (
+	FlagA Level = 1 << iota
+	FlagB
+)
+
+type MyBool bool
+
+const limit = 10
+
+func retry(r Retries) {}
+func level(l Level)   {}
+func count(i int)     {}
+
+func main() {
+	time.Sleep(2 * time.Second)
+	time.Sleep(-time.Second)
+	time.Sleep((time.Minute))
+	time.Sleep(timeout)
+	retry(retries)
+	retry(Retries(3) * 2)
+	level(High)
+	level(FlagA | FlagB)
+	count(2 * 60)
+	var d time.Duration
+	time.Sleep(d * 2)
+
+	// Comparisons of constants are booleans
+	var b MyBool = 1 < 2
+	b = limit >= 5 && 2.5 != 3
+	_ = b
+}
@@ -0,0 +1,7 @@
+package fixconvertbad
+
+import . "os"
+
+func dotImport() {
+	_ = Chmod("x", 0644) // want "Implicit literal cast of INT to os.FileMode"
+}
@@ -0,0 +1,13 @@
+package fixconvertbad
+
+type Level int
+
+type Port int
+
+func level(l Level) {}
+
+func main() {
+	level(3)               // want "Implicit literal cast of INT to fixconvertbad.Level"
+	level((4))             // want "Implicit literal cast of INT to fixconvertbad.Level"
+	_ = []Port{80, 2 * 40} // want "Implicit literal cast of INT to fixconvertbad.Port" "Implicit literal cast of INT to fixconvertbad.Port"
+}
@@ -0,0 +1,7 @@
+package fixconvertbad
+
+import sys "os"
+
+func renamed() {
+	_ = sys.Chmod("x", 0644) // want "Implicit literal cast of INT to os.FileMode"
+}
@@ -0,0 +1,8 @@
+package fixdurationbad
+
+import "time"
+
+func main() {
+	time.Sleep(4)     // want "Implicit literal cast of INT to time.Duration"
+	time.Sleep(2 * 3) // want "Implicit literal cast of INT to time.Duration"
+}
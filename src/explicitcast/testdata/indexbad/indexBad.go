@@ -0,0 +1,14 @@
+package indexbad
+
+type Port int
+
+type Level int
+
+func main() {
+	ports := make([]Port, 3)
+	idx := 1
+	ports[idx] = 1 // want "Implicit literal cast of INT to indexbad.Port"
+	names := map[Level]string{}
+	names[3] = "x" // want "Implicit literal cast of INT to indexbad.Level"
+	_ = names[4]   // want "Implicit literal cast of INT to indexbad.Level"
+}
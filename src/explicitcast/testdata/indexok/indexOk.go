@@ -0,0 +1,17 @@
+package indexok
+
+type Port int
+
+type Level int
+
+func main() {
+	ports := make([]Port, 3)
+	idx := 1
+	ports[idx] = Port(1)
+	ports[0] = Port(1)
+	names := map[Level]string{}
+	names[Level(3)] = "x"
+	_ = names[Level(4)]
+	counts := map[string]int{}
+	counts["x"] = 1
+}
@@ -0,0 +1,8 @@
+package sendbad
+
+import "time"
+
+func main() {
+	ch := make(chan time.Duration, 1)
+	ch <- 5 // want "Implicit literal cast of INT to time.Duration"
+}
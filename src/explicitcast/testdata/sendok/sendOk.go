@@ -0,0 +1,10 @@
+package sendok
+
+import "time"
+
+func main() {
+	ch := make(chan time.Duration, 1)
+	ch <- 5 * time.Second
+	ints := make(chan int, 1)
+	ints <- 5
+}
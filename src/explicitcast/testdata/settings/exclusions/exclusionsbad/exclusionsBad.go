@@ -0,0 +1,17 @@
+package exclusionsbad
+
+import (
+	"time"
+
+	"exclusionsbad/flags"
+)
+
+type Level int
+
+func level(l Level) {}
+
+func main() {
+	flags.SetStrict(3) // want "Implicit literal cast of INT to exclusionsbad/flags.Strict"
+	level(3)           // want "Implicit literal cast of INT to exclusionsbad.Level"
+	time.Sleep(5)      // want "Implicit literal cast of INT to time.Duration"
+}
@@ -0,0 +1,15 @@
+package exclusionsok
+
+import (
+	"os"
+	"time"
+
+	"exclusionsok/flags"
+)
+
+func main() {
+	_ = os.Chmod("x", 0644)
+	flags.Set(3)
+	flags.SetStrict(flags.Strict(3))
+	time.Sleep(5 * time.Second)
+}
@@ -0,0 +1,9 @@
+package flags
+
+type Mode int
+
+type Strict int
+
+func Set(m Mode) {}
+
+func SetStrict(s Strict) {}
@@ -0,0 +1,9 @@
+package money
+
+type Amount int64
+
+type Cents = Amount
+
+func Pay(a Amount) {}
+
+func Refund(c Cents) {}
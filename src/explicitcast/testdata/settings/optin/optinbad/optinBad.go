@@ -0,0 +1,21 @@
+package optinbad
+
+import (
+	"time"
+
+	"optinbad/money"
+	"optinbad/units"
+)
+
+//vinego:check explicitcast
+type Level int // want Level:"checks\\(explicitcast\\)"
+
+func level(l Level) {}
+
+func main() {
+	level(3)        // want "Implicit literal cast of INT to optinbad.Level"
+	time.Sleep(5)   // want "Implicit literal cast of INT to time.Duration"
+	money.Pay(100)  // want "Implicit literal cast of INT to optinbad/money.Amount"
+	money.Refund(1) // want "Implicit literal cast of INT to optinbad/money.Cents"
+	units.Scale(2)  // want "Implicit literal cast of INT to optinbad/units.Meters"
+}
@@ -0,0 +1,6 @@
+package units
+
+//vinego:check explicitcast
+type Meters float64
+
+func Scale(m Meters) {}
@@ -0,0 +1,17 @@
+package optinok
+
+import "os"
+
+//vinego:check explicitcast
+type Allowed int // want Allowed:"checks\\(explicitcast\\)"
+
+type Plain int
+
+func allowed(a Allowed) {}
+func plain(p Plain)     {}
+
+func main() {
+	allowed(3)
+	plain(3)
+	_ = os.Chmod("x", 0644)
+}
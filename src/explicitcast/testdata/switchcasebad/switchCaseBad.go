@@ -0,0 +1,11 @@
+package switchcasebad
+
+type Level int
+
+func main() {
+	var l Level
+	switch l {
+	case 3: // want "Implicit literal cast of INT to switchcasebad.Level"
+	case Level(4), 5: // want "Implicit literal cast of INT to switchcasebad.Level"
+	}
+}
@@ -0,0 +1,18 @@
+package switchcaseok
+
+type Level int
+
+func main() {
+	var l Level
+	switch l {
+	case Level(3):
+	case Level(4), Level(5):
+	}
+	var i int
+	switch i {
+	case 3:
+	}
+	switch {
+	case l == Level(3):
+	}
+}
@@ -0,0 +1,20 @@
+package typeparambad
+
+type Integer interface {
+	~int | ~int64
+}
+
+type Level int
+
+func zero[T Integer]() T {
+	return 0 // want "Implicit literal cast of INT to T"
+}
+
+func mixed[T int | Level](v T) bool {
+	return v == 3 // want "Implicit literal cast of INT to T"
+}
+
+func main() {
+	_ = zero[Level]()
+	_ = mixed(Level(1))
+}
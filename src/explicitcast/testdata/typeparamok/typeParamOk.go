@@ -0,0 +1,24 @@
+package typeparamok
+
+type Number interface {
+	int | int64 | float64
+}
+
+type Level int
+
+func sum[T Number](values []T) T {
+	var total T = 0
+	for _, v := range values {
+		total += v
+	}
+	return total + 1
+}
+
+func identity[T ~int](v T) T { return v }
+
+func main() {
+	_ = sum([]int{1, 2})
+	// Inferred from the constant's default type
+	_ = identity(4)
+	_ = identity(Level(4))
+}
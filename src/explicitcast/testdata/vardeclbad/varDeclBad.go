@@ -0,0 +1,13 @@
+package vardeclbad
+
+import "time"
+
+type T int
+
+var global T = 4 // want "Implicit literal cast of INT to vardeclbad.T"
+
+func main() {
+	var d time.Duration = 5 // want "Implicit literal cast of INT to time.Duration"
+	var a, b T = 1, T(2)    // want "Implicit literal cast of INT to vardeclbad.T"
+	_, _, _ = d, a, b
+}
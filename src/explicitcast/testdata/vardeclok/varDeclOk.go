@@ -0,0 +1,15 @@
+package vardeclok
+
+import "time"
+
+type T int
+
+var global T = T(4)
+
+func main() {
+	var d time.Duration = 5 * time.Second
+	var i = 4
+	var j int = 4
+	var a, b T = T(1), T(2)
+	_, _, _, _, _ = d, i, j, a, b
+}
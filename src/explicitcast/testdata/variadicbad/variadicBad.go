@@ -0,0 +1,10 @@
+package variadicbad
+
+type Port int
+
+func listen(name string, ports ...Port) {}
+
+func main() {
+	listen("a", Port(80), 443) // want "Implicit literal cast of INT to variadicbad.Port"
+	listen("a", 80)            // want "Implicit literal cast of INT to variadicbad.Port"
+}
@@ -0,0 +1,15 @@
+package variadicok
+
+import "fmt"
+
+type Port int
+
+func listen(ports ...Port) {}
+func count(values ...int)  {}
+
+func main() {
+	listen(Port(80), Port(443))
+	listen([]Port{Port(80)}...)
+	count(1, 2, 3)
+	fmt.Println(1, "a", 2.5)
+}
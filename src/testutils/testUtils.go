@@ -1,7 +1,6 @@
 package testutils
 
 import (
-	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
@@ -13,42 +12,106 @@ import (
 	"golang.org/x/tools/go/analysis/analysistest"
 )
 
+// Runs the analyzer on each test case in `testdata`.  A test case is a top level directory, the
+// package of which is analyzed.  Files in subdirectories are written too so they can be imported by
+// the test case package as `<case>/<subdir>`.  If any `.golden` files are present, suggested fixes are
+// checked against them as well.
 func RunTests(t *testing.T, analyzer *analysis.Analyzer, filter map[string]bool) {
-	root := "testdata"
-	err := filepath.Walk(root, func(path string, info fs.FileInfo, err0 error) error {
+	RunTestsIn(t, "testdata", analyzer, filter)
+}
+
+// Like RunTests but with test cases in root.  Top level directories without Go files of their own
+// are skipped, so test cases needing a differently configured analyzer can be grouped in a
+// subdirectory of `testdata`.  Files in the `_deps` directory are written for every test case, at
+// the root of the package tree, so stand-ins for third party packages can be imported with their
+// usual path, ex: `_deps/golang.org/x/sync/errgroup`.
+func RunTestsIn(t *testing.T, root string, analyzer *analysis.Analyzer, filter map[string]bool) {
+	cases, err := os.ReadDir(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	depsRoot := filepath.Join(root, "_deps")
+	deps := map[string]string{}
+	err = filepath.Walk(depsRoot, func(path string, info fs.FileInfo, err0 error) error {
 		if err0 != nil {
+			if os.IsNotExist(err0) {
+				return nil
+			}
 			return err0
 		}
-		if !strings.HasSuffix(path, ".go") {
-			return nil
-		}
-		if filter != nil && !filter[utils.Last(strings.Split(path, "/"))] {
+		if info.IsDir() {
 			return nil
 		}
 		contents, err := os.ReadFile(path)
 		if err != nil {
-			t.Errorf("failed to load test case %s: %s", path, err)
-			return nil
-		}
-		relPath, _ := filepath.Rel(root, path)
-		dir, cleanup, err := analysistest.WriteFiles(map[string]string{
-			relPath: string(contents),
-		})
-		if err != nil {
-			t.Errorf("failed to prep temp test dir: %s", err)
-			return nil
-		}
-		defer cleanup()
-		fmt.Printf("========= at %s\n", path)
-		results := analysistest.Run(t, dir, analyzer, filepath.Base(filepath.Dir(path)))
-		for _, res := range results {
-			if res.Err != nil {
-				t.Errorf("analyzer failed on %s: %s", path, res.Err)
-			}
+			return err
 		}
+		relPath, _ := filepath.Rel(depsRoot, path)
+		deps[relPath] = string(contents)
 		return nil
 	})
 	if err != nil {
 		t.Fatal(err)
 	}
+	for _, testCase := range cases {
+		if !testCase.IsDir() {
+			continue
+		}
+		caseRoot := filepath.Join(root, testCase.Name())
+		files := map[string]string{}
+		utils.MergeMap(files, deps)
+		selected := filter == nil
+		hasGolden := false
+		hasGo := false
+		err := filepath.Walk(caseRoot, func(path string, info fs.FileInfo, err0 error) error {
+			if err0 != nil {
+				return err0
+			}
+			if info.IsDir() {
+				return nil
+			}
+			if strings.HasSuffix(path, ".go") && filter != nil && filter[utils.Last(strings.Split(path, "/"))] {
+				selected = true
+			}
+			if strings.HasSuffix(path, ".go") && filepath.Dir(path) == caseRoot {
+				hasGo = true
+			}
+			if strings.HasSuffix(path, ".golden") {
+				hasGolden = true
+			}
+			contents, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			relPath, _ := filepath.Rel(root, path)
+			files[relPath] = string(contents)
+			return nil
+		})
+		if err != nil {
+			t.Errorf("failed to load test case %s: %s", caseRoot, err)
+			continue
+		}
+		if !hasGo || !selected {
+			continue
+		}
+		func() {
+			dir, cleanup, err := analysistest.WriteFiles(files)
+			if err != nil {
+				t.Errorf("failed to prep temp test dir: %s", err)
+				return
+			}
+			defer cleanup()
+			var results []*analysistest.Result
+			if hasGolden {
+				results = analysistest.RunWithSuggestedFixes(t, dir, analyzer, testCase.Name())
+			} else {
+				results = analysistest.Run(t, dir, analyzer, testCase.Name())
+			}
+			for _, res := range results {
+				if res.Err != nil {
+					t.Errorf("analyzer failed on %s: %s", caseRoot, res.Err)
+				}
+			}
+		}()
+	}
 }
@@ -0,0 +1,177 @@
+package utils
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Directives are line comments like `//vinego:check allfields except=Debug`: a verb, then
+// positional arguments and `key=value,value` arguments separated by spaces.  Anything after a
+// further ` //` is a comment.
+const DirectivePrefix = "//vinego:"
+
+// Strips a trailing ` // comment` from a line comment's text
+func StripTrailingComment(text string) string {
+	before, _, _ := strings.Cut(text, " //")
+	return before
+}
+
+// Where a directive is attached
+type Placement string
+
+const (
+	PlacementPackage Placement = "package"
+	PlacementImport  Placement = "import"
+	PlacementType    Placement = "type"
+	PlacementFunc    Placement = "func"
+	PlacementMethod  Placement = "method"
+	// Methods with a pointer receiver are methods too
+	PlacementPointerMethod Placement = "pointer receiver method"
+	PlacementVar           Placement = "var"
+	PlacementField         Placement = "field"
+)
+
+// Directive verbs, ex: `check` in `//vinego:check`
+var KnownDirectives = map[string]bool{
+	"check": true,
+}
+
+//vinego:check allfields
+type CheckSpec struct {
+	// Declarations the check can be attached to
+	Placements []Placement
+	// Accepted argument keys
+	Args []string
+}
+
+// Whether the check can be attached to a declaration with the placement
+func (s CheckSpec) Allows(placement Placement) bool {
+	if placement == PlacementPointerMethod && slices.Contains(s.Placements, PlacementMethod) {
+		return true
+	}
+	return slices.Contains(s.Placements, placement)
+}
+
+// Returns the placement of a function or method declaration
+func FuncPlacement(decl *ast.FuncDecl) Placement {
+	if decl.Recv == nil || len(decl.Recv.List) == 0 {
+		return PlacementFunc
+	}
+	if _, isPointer := ast.Unparen(decl.Recv.List[0].Type).(*ast.StarExpr); isPointer {
+		return PlacementPointerMethod
+	}
+	return PlacementMethod
+}
+
+// Checks that can be enabled with `//vinego:check`
+var KnownChecks = map[string]CheckSpec{
+	"allfields": {
+		Placements: []Placement{PlacementType, PlacementPackage},
+		Args:       []string{"except"},
+	},
+	"allfields-deep": {
+		Placements: []Placement{PlacementType, PlacementPackage},
+		Args:       []string{"except"},
+	},
+	"nozero": {
+		Placements: []Placement{PlacementType, PlacementPackage},
+		Args:       []string{},
+	},
+	"zerook": {
+		Placements: []Placement{PlacementType},
+		Args:       []string{},
+	},
+	"initializer": {
+		Placements: []Placement{PlacementPointerMethod},
+		Args:       []string{},
+	},
+	"noreturn": {
+		Placements: []Placement{PlacementFunc, PlacementMethod},
+		Args:       []string{},
+	},
+	"explicitcast": {
+		Placements: []Placement{PlacementType},
+		Args:       []string{},
+	},
+}
+
+// Arguments of a check, ex: `except=A,B` becomes {"except": ["A", "B"]}
+type CheckArgs map[string][]string
+
+//vinego:check allfields
+type Directive struct {
+	Pos token.Pos
+	// Ex: `check`
+	Verb string
+	// Arguments without a key, ex: `allfields`
+	Names []string
+	Args  CheckArgs
+}
+
+var directiveWordRegexp = regexp.MustCompile("^[a-z][a-z0-9-]*$")
+
+// Parses a `//vinego:` comment.  Returns nil if the comment isn't a directive and an error if it's
+// malformed.
+func ParseDirective(c *ast.Comment) (*Directive, error) {
+	if !strings.HasPrefix(c.Text, DirectivePrefix) {
+		return nil, nil
+	}
+	words := strings.Fields(StripTrailingComment(strings.TrimPrefix(c.Text, DirectivePrefix)))
+	if len(words) == 0 || !directiveWordRegexp.MatchString(words[0]) {
+		return nil, fmt.Errorf("missing or invalid directive name after %s", DirectivePrefix)
+	}
+	out := &Directive{
+		Pos:   c.Pos(),
+		Verb:  words[0],
+		Names: []string{},
+		Args:  CheckArgs{},
+	}
+	for _, word := range words[1:] {
+		key, value, isArg := strings.Cut(word, "=")
+		if !isArg {
+			if !directiveWordRegexp.MatchString(word) {
+				return nil, fmt.Errorf("invalid argument `%s`", word)
+			}
+			Append(&out.Names, word)
+			continue
+		}
+		if !directiveWordRegexp.MatchString(key) {
+			return nil, fmt.Errorf("invalid argument key in `%s`", word)
+		}
+		if _, duplicate := out.Args[key]; duplicate {
+			return nil, fmt.Errorf("argument `%s` specified more than once", key)
+		}
+		values := []string{}
+		for v := range strings.SplitSeq(value, ",") {
+			if v == "" {
+				return nil, fmt.Errorf("empty value in `%s`", word)
+			}
+			Append(&values, v)
+		}
+		out.Args[key] = values
+	}
+	return out, nil
+}
+
+// Returns the well formed `//vinego:check` directives in the comment group
+func CheckDirectives(group *ast.CommentGroup) []*Directive {
+	out := []*Directive{}
+	if group == nil {
+		return out
+	}
+	for _, c := range group.List {
+		directive, err := ParseDirective(c)
+		if directive == nil || err != nil {
+			continue
+		}
+		if directive.Verb != "check" || len(directive.Names) != 1 {
+			continue
+		}
+		Append(&out, directive)
+	}
+	return out
+}
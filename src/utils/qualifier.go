@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// Maps import paths to the name the package is visible as in the file ("." for dot imports).  Blank
+// imports are omitted.
+func ImportNames(p *analysis.Pass, file *ast.File) map[string]string {
+	out := map[string]string{}
+	for _, spec := range file.Imports {
+		pkgName := p.TypesInfo.PkgNameOf(spec)
+		if pkgName == nil || pkgName.Name() == "_" {
+			continue
+		}
+		out[pkgName.Imported().Path()] = pkgName.Name()
+	}
+	return out
+}
+
+// Returns a qualifier for writing types as they'd be spelled in file.  If a type refers to a package
+// that isn't imported in the file `unresolved` is set, since the output won't compile as is.
+func FileQualifier(p *analysis.Pass, file *ast.File, unresolved *bool) types.Qualifier {
+	names := ImportNames(p, file)
+	return func(pkg *types.Package) string {
+		if pkg == p.Pkg {
+			return ""
+		}
+		name, imported := names[pkg.Path()]
+		if !imported {
+			*unresolved = true
+			return pkg.Name()
+		}
+		if name == "." {
+			return ""
+		}
+		return name
+	}
+}
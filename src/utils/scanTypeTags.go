@@ -4,52 +4,140 @@ import (
 	"go/ast"
 	"go/types"
 	"regexp"
+	"slices"
 	"strings"
 
 	"golang.org/x/tools/go/analysis"
 )
 
-type ChecksFact map[string]bool
+// Checks enabled on an object, with their arguments
+type ChecksFact map[string]CheckArgs
 
 func (*ChecksFact) AFact() {}
 
-var checkRegexp = regexp.MustCompile("check:([a-z]+)")
+func (f *ChecksFact) String() string {
+	names := []string{}
+	for name := range *f {
+		Append(&names, name)
+	}
+	slices.Sort(names)
+	return "checks(" + strings.Join(names, ",") + ")"
+}
+
+func (f ChecksFact) Has(check string) bool {
+	_, has := f[check]
+	return has
+}
+
+// Matches legacy tags anywhere in doc comments
+var LegacyCheckRegexp = regexp.MustCompile("check:([a-z-]+)")
+
+// Returns the checks enabled by directives in the doc comment.  If legacy is set, `check:name` tags
+// in the comment text are recognized too.
+func DocChecks(doc *ast.CommentGroup, legacy bool) ChecksFact {
+	out := ChecksFact{}
+	if doc == nil {
+		return out
+	}
+	for _, directive := range CheckDirectives(doc) {
+		out[directive.Names[0]] = directive.Args
+	}
+	if legacy {
+		for line := range strings.SplitSeq(doc.Text(), "\n") {
+			matches := LegacyCheckRegexp.FindStringSubmatch(line)
+			if len(matches) >= 2 && !out.Has(matches[1]) {
+				out[matches[1]] = CheckArgs{}
+			}
+		}
+	}
+	return out
+}
+
+// Returns the checks that can be placed on the declaration, see KnownChecks.  Others are reported by
+// the directives analyzer.
+func placedChecks(enabledChecks ChecksFact, placement Placement) ChecksFact {
+	out := ChecksFact{}
+	for name, args := range enabledChecks {
+		if spec, known := KnownChecks[name]; known && spec.Allows(placement) {
+			out[name] = args
+		}
+	}
+	return out
+}
 
-func ScanTypeTags(p *analysis.Pass) {
+// Exports the checks enabled on declarations as facts: types, functions and methods, and interface
+// methods as object facts, and checks on the package clause (in any file) as a package fact.  Only
+// checks that can be placed on the declaration are exported.  Legacy tags are only recognized on
+// types.
+func ScanTags(p *analysis.Pass, legacy bool) {
+	export := func(ident *ast.Ident, placement Placement, enabledChecks ChecksFact) {
+		enabledChecks = placedChecks(enabledChecks, placement)
+		if len(enabledChecks) == 0 {
+			return
+		}
+		obj := p.TypesInfo.Defs[ident]
+		if obj == nil {
+			return
+		}
+		p.ExportObjectFact(obj, &enabledChecks)
+	}
+	packageChecks := ChecksFact{}
 	for _, file := range p.Files {
+		MergeMap(packageChecks, DocChecks(file.Doc, false))
 		ast.Inspect(file, func(n0 ast.Node) bool {
-			decl, isGenDecl := n0.(*ast.GenDecl)
-			if !isGenDecl {
-				return true
-			}
-			if decl.Doc == nil {
-				return true
-			}
-			enabledChecks := &ChecksFact{}
-			for line := range strings.SplitSeq(decl.Doc.Text(), "\n") {
-				matches := checkRegexp.FindStringSubmatch(line)
-				if len(matches) >= 2 {
-					(*enabledChecks)[matches[1]] = true
+			switch n := n0.(type) {
+			case *ast.GenDecl:
+				declChecks := DocChecks(n.Doc, legacy)
+				for _, declElem0 := range n.Specs {
+					typeSpec, isTypeSpec := declElem0.(*ast.TypeSpec)
+					if !isTypeSpec {
+						continue
+					}
+					enabledChecks := ChecksFact{}
+					MergeMap(enabledChecks, declChecks)
+					MergeMap(enabledChecks, DocChecks(typeSpec.Doc, legacy))
+					export(typeSpec.Name, PlacementType, enabledChecks)
 				}
-			}
-			if len(*enabledChecks) == 0 {
-				return true
-			}
-			for _, declElem0 := range decl.Specs {
-				typeSpec, isTypeSpec := declElem0.(*ast.TypeSpec)
-				if !isTypeSpec {
-					continue
+			case *ast.FuncDecl:
+				export(n.Name, FuncPlacement(n), DocChecks(n.Doc, false))
+			case *ast.InterfaceType:
+				for _, method := range n.Methods.List {
+					for _, name := range method.Names {
+						export(name, PlacementMethod, DocChecks(method.Doc, false))
+					}
 				}
-				declDef := p.TypesInfo.Defs[typeSpec.Name]
-				p.ExportObjectFact(declDef, enabledChecks)
 			}
 			return true
 		})
 	}
+	packageChecks = placedChecks(packageChecks, PlacementPackage)
+	if len(packageChecks) > 0 {
+		p.ExportPackageFact(&packageChecks)
+	}
 }
 
-func GetTypeTags(p *analysis.Pass, o types.Object) ChecksFact {
+// Returns the checks enabled for the object.  Checks enabled on a package apply to all of its
+// exported struct types, unless the type has its own directive for the check.  The result is a
+// copy and can be modified.
+func GetTags(p *analysis.Pass, o types.Object) ChecksFact {
+	out := ChecksFact{}
+	if typeName, isTypeName := o.(*types.TypeName); isTypeName && typeName.Exported() && typeName.Pkg() != nil {
+		if _, isStruct := typeName.Type().Underlying().(*types.Struct); isStruct {
+			packageChecks := new(ChecksFact)
+			p.ImportPackageFact(typeName.Pkg(), packageChecks)
+			MergeMap(out, *packageChecks)
+		}
+	}
 	enabledChecks := new(ChecksFact)
 	p.ImportObjectFact(o, enabledChecks)
-	return *enabledChecks
+	MergeMap(out, *enabledChecks)
+	return out
+}
+
+// Returns the name of the object qualified by the full package path, ex: `net/http.Server`
+func QualifiedName(o types.Object) string {
+	if o.Pkg() == nil {
+		return o.Name()
+	}
+	return o.Pkg().Path() + "." + o.Name()
 }
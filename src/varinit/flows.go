@@ -0,0 +1,95 @@
+package varinit
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/ctrlflow"
+	"golang.org/x/tools/go/cfg"
+	"golang.org/x/tools/go/types/typeutil"
+
+	"github.com/upsun/vinego/src/utils"
+)
+
+// Builds control flow graphs where calls of functions that never return end their block, so the
+// rest of the block is unreachable and it doesn't flow into the following code.  In addition to
+// `panic` and the functions `ctrlflow` knows don't return (`os.Exit`, `log.Fatal`, `t.FailNow`,
+// ...), functions and interface methods marked with `//vinego:check noreturn` and functions named
+// in the settings don't return.
+//
+//vinego:check allfields
+type Flows struct {
+	p    *analysis.Pass
+	cfgs *ctrlflow.CFGs
+	// Full names of functions that don't return, see `types.Func.FullName`
+	noReturn map[string]bool
+	graphs   map[*ast.BlockStmt]*cfg.CFG
+	// The communication statements of `select` cases.  The graphs evaluate all of them before the
+	// select, but only their channel operands and sent values are evaluated there.
+	selectComms map[ast.Stmt]bool
+}
+
+func NewFlows(p *analysis.Pass, cfgs *ctrlflow.CFGs, noReturn []string) *Flows {
+	noReturnSet := map[string]bool{}
+	for _, name := range noReturn {
+		noReturnSet[name] = true
+	}
+	return &Flows{
+		p:           p,
+		cfgs:        cfgs,
+		noReturn:    noReturnSet,
+		graphs:      map[*ast.BlockStmt]*cfg.CFG{},
+		selectComms: map[ast.Stmt]bool{},
+	}
+}
+
+func (f *Flows) MayReturn(call *ast.CallExpr) bool {
+	if ident, isIdent := ast.Unparen(call.Fun).(*ast.Ident); isIdent {
+		if builtin, isBuiltin := f.p.TypesInfo.Uses[ident].(*types.Builtin); isBuiltin && builtin.Name() == "panic" {
+			return false
+		}
+	}
+	// Includes interface methods, which can be marked too
+	fn, isFunc := typeutil.Callee(f.p.TypesInfo, call).(*types.Func)
+	if !isFunc {
+		return true
+	}
+	if f.cfgs.NoReturn(fn) || f.cfgs.NoReturn(fn.Origin()) {
+		return false
+	}
+	fn = fn.Origin()
+	return !f.noReturn[fn.FullName()] && !utils.GetTags(f.p, fn).Has("noreturn")
+}
+
+func (f *Flows) body(body *ast.BlockStmt) *cfg.CFG {
+	if body == nil {
+		return nil
+	}
+	graph, built := f.graphs[body]
+	if !built {
+		graph = cfg.New(body, f.MayReturn)
+		f.graphs[body] = graph
+		ast.Inspect(body, func(n ast.Node) bool {
+			if clause, isComm := n.(*ast.CommClause); isComm && clause.Comm != nil {
+				f.selectComms[clause.Comm] = true
+			}
+			return true
+		})
+	}
+	return graph
+}
+
+// Whether the statement is the send or receive of a `select` case
+func (f *Flows) SelectComm(stmt ast.Stmt) bool {
+	return f.selectComms[stmt]
+}
+
+// Returns nil for functions without a body
+func (f *Flows) FuncDecl(decl *ast.FuncDecl) *cfg.CFG {
+	return f.body(decl.Body)
+}
+
+func (f *Flows) FuncLit(lit *ast.FuncLit) *cfg.CFG {
+	return f.body(lit.Body)
+}
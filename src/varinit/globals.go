@@ -0,0 +1,113 @@
+package varinit
+
+import (
+	"go/ast"
+	"go/token"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/upsun/vinego/src/utils"
+)
+
+// Returns the package's `init` functions, in the order they run
+func InitFuncs(p *analysis.Pass) []*ast.FuncDecl {
+	out := []*ast.FuncDecl{}
+	for _, file := range p.Files {
+		for _, decl := range file.Decls {
+			funcDecl, isFuncDecl := decl.(*ast.FuncDecl)
+			if isFuncDecl && funcDecl.Recv == nil && funcDecl.Name.Name == "init" {
+				utils.Append(&out, funcDecl)
+			}
+		}
+	}
+	return out
+}
+
+// Checks the package level variables declared without a value across all files of the package.
+// They're initialized if a package level initializer writes them (ex: `var _ =
+// json.Unmarshal(data, &config)`), or once the `init` functions, which run in order after all the
+// initializers, have assigned them on every path.  Reads in initializers and `init` functions
+// before that are reported where they happen, other variables are reported once at their
+// declaration.
+func EvalGlobals(a *Analysis) {
+	p := a.p
+	scope := &Scope{
+		Location:      BranchId(token.NoPos),
+		Comment:       "declaration",
+		Uninitialized: map[VarId]*Decl{},
+	}
+	c := &Context{
+		Analysis: a,
+		scope:    scope,
+		block:    nil,
+		deferred: nil,
+	}
+	specs := []*ast.ValueSpec{}
+	for _, file := range p.Files {
+		for _, decl := range file.Decls {
+			genDecl, isGenDecl := decl.(*ast.GenDecl)
+			if !isGenDecl || genDecl.Tok != token.VAR {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				utils.Append(&specs, spec.(*ast.ValueSpec))
+			}
+		}
+	}
+
+	// Declare everything first, initializers can write variables declared later
+	for _, spec := range specs {
+		if len(spec.Values) > 0 {
+			continue
+		}
+		for _, name := range spec.Names {
+			obj := p.TypesInfo.Defs[name]
+			if obj == nil {
+				continue
+			}
+			// Point reads in init functions to the declaration
+			scope.Location = BranchId(name.Pos())
+			scope.NewDecl(a, name)
+		}
+	}
+	scope.Location = BranchId(token.NoPos)
+
+	// Initializers run in dependency order before the init functions, so variables they read must be
+	// initialized by an earlier initializer
+	for _, initializer := range p.TypesInfo.InitOrder {
+		EvalExpr(c, initializer.Rhs)
+	}
+
+	inits := InitFuncs(p)
+	initStrings := []string{}
+	for _, init := range inits {
+		utils.Append(&initStrings, " - "+p.Fset.Position(init.Name.Pos()).String())
+		flow := a.flows.FuncDecl(init)
+		if flow == nil {
+			continue
+		}
+		scope = EvalFunc(a, flow, init.Type, []*Scope{scope})
+	}
+
+	uninitialized := []token.Pos{}
+	for id, decl := range scope.Uninitialized {
+		if len(decl.Uninitialized) == 0 || a.reported[id] || a.reported[VarId{Pos: id.Pos, Field: ""}] {
+			continue
+		}
+		utils.Append(&uninitialized, id.Pos)
+	}
+	slices.Sort(uninitialized)
+	uninitialized = slices.Compact(uninitialized)
+	message := "This variable was never explicitly initialized"
+	if len(initStrings) > 0 {
+		message += ", by its declaration or on every path of the init functions:\n" + strings.Join(initStrings, "\n") + "\n"
+	}
+	for _, pos := range uninitialized {
+		p.Report(analysis.Diagnostic{
+			Pos:     pos,
+			Message: message,
+		})
+	}
+}
@@ -0,0 +1,331 @@
+package varinit
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+
+	"golang.org/x/tools/go/cfg"
+	"golang.org/x/tools/go/types/typeutil"
+
+	"github.com/upsun/vinego/src/utils"
+)
+
+// Identifies a WaitGroup, errgroup.Group or channel by the variable it's stored in and the fields
+// selected from it, so `a.wg` and `b.wg` are different
+//
+//vinego:check allfields
+type JoinKey struct {
+	// Nil if the expression isn't rooted at a variable
+	Root types.Object
+	// Fields selected from the variable, like `a.b`, empty for the variable itself
+	Path string
+}
+
+// An initialization done by a goroutine, which can be relied on after a join point: waiting for the
+// WaitGroup or errgroup.Group the goroutine was started with or calls `Done` on, or receiving from
+// a channel the goroutine sends to or closes
+//
+//vinego:check allfields
+type PendingWrite struct {
+	Join JoinKey
+	// Where the goroutine is started
+	Start token.Pos
+}
+
+// Where a goroutine signals a join point
+//
+//vinego:check allfields
+type Signal struct {
+	Join JoinKey
+	// The send, `Done` or `close` call, nil when signaled as the goroutine returns, ex: deferred
+	Node ast.Node
+}
+
+// Methods starting their function argument in a goroutine, joined by waiting for the receiver
+var goMethods = map[string]bool{
+	"(*sync.WaitGroup).Go":                   true,
+	"(*golang.org/x/sync/errgroup.Group).Go": true,
+}
+
+// Methods waiting for the goroutines started with or marked done on the receiver
+var waitMethods = map[string]bool{
+	"(*sync.WaitGroup).Wait":                   true,
+	"(*golang.org/x/sync/errgroup.Group).Wait": true,
+}
+
+var doneMethods = map[string]bool{
+	"(*sync.WaitGroup).Done": true,
+}
+
+// Returns the key of a WaitGroup, group or channel expression, with a nil root if it isn't a
+// variable or field of one
+func joinKey(c *Context, e ast.Expr) JoinKey {
+	none := JoinKey{Root: nil, Path: ""}
+	switch e1 := ast.Unparen(e).(type) {
+	case *ast.Ident:
+		return JoinKey{Root: c.p.TypesInfo.Uses[e1], Path: ""}
+	case *ast.SelectorExpr:
+		sel := c.p.TypesInfo.Selections[e1]
+		if sel == nil {
+			// Package qualified variable
+			return JoinKey{Root: c.p.TypesInfo.Uses[e1.Sel], Path: ""}
+		}
+		if sel.Kind() != types.FieldVal {
+			return none
+		}
+		key := joinKey(c, e1.X)
+		if key.Root == nil {
+			return none
+		}
+		if key.Path != "" {
+			key.Path += "."
+		}
+		key.Path += e1.Sel.Name
+		return key
+	case *ast.UnaryExpr:
+		if e1.Op == token.AND {
+			return joinKey(c, e1.X)
+		}
+	case *ast.StarExpr:
+		return joinKey(c, e1.X)
+	}
+	return none
+}
+
+// Returns the receiver key if the call is of one of the methods, like `wg` in `wg.Wait()`
+func methodCallReceiver(c *Context, call *ast.CallExpr, methods map[string]bool) JoinKey {
+	sel, isSel := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+	if !isSel {
+		return JoinKey{Root: nil, Path: ""}
+	}
+	fn, isFunc := typeutil.Callee(c.p.TypesInfo, call).(*types.Func)
+	if !isFunc || !methods[fn.Origin().FullName()] {
+		return JoinKey{Root: nil, Path: ""}
+	}
+	return joinKey(c, sel.X)
+}
+
+// Returns the function started by a call like `wg.Go(func() { ... })` and what joins it
+func GoMethodCall(c *Context, call *ast.CallExpr) (*ast.FuncLit, JoinKey, bool) {
+	join := methodCallReceiver(c, call, goMethods)
+	if join.Root == nil || len(call.Args) != 1 {
+		return nil, join, false
+	}
+	lit, isLit := ast.Unparen(call.Args[0]).(*ast.FuncLit)
+	if !isLit {
+		return nil, join, false
+	}
+	return lit, join, true
+}
+
+// Returns the signals of the joins a goroutine started with `go func() { ... }()` signals on every
+// path that returns: WaitGroups it calls `Done` on and channels it sends to or closes
+func GoroutineJoins(c *Context, lit *ast.FuncLit) []Signal {
+	flow := c.flows.FuncLit(lit)
+	if flow == nil {
+		return []Signal{}
+	}
+	candidates := []JoinKey{}
+	blockSignalNodes := map[*cfg.Block][]Signal{}
+	signals := map[*cfg.Block]map[JoinKey]bool{}
+	preds := map[*cfg.Block][]*cfg.Block{}
+	for _, b := range flow.Blocks {
+		if !b.Live {
+			continue
+		}
+		for _, succ := range b.Succs {
+			preds[succ] = append(preds[succ], b)
+		}
+		signals[b] = map[JoinKey]bool{}
+		blockSignalNodes[b] = blockSignals(c, b)
+		for _, signal := range blockSignalNodes[b] {
+			if !slices.Contains(candidates, signal.Join) {
+				utils.Append(&candidates, signal.Join)
+			}
+			signals[b][signal.Join] = true
+		}
+	}
+	out := []Signal{}
+	for _, join := range candidates {
+		// Whether the join was signaled at the end of each block on all paths, starting from all of
+		// them and removing the blocks reachable without it until nothing changes
+		signaled := map[*cfg.Block]bool{}
+		for b := range signals {
+			signaled[b] = true
+		}
+		for changed := true; changed; {
+			changed = false
+			for b := range signals {
+				if !signaled[b] || signals[b][join] {
+					continue
+				}
+				if b == flow.Blocks[0] || slices.ContainsFunc(preds[b], func(pred *cfg.Block) bool { return !signaled[pred] }) {
+					signaled[b] = false
+					changed = true
+				}
+			}
+		}
+		joins := true
+		for b := range signals {
+			if len(b.Succs) == 0 && b.Return() != nil && !signaled[b] {
+				joins = false
+				break
+			}
+		}
+		if !joins {
+			continue
+		}
+		for _, b := range flow.Blocks {
+			for _, signal := range blockSignalNodes[b] {
+				if signal.Join == join {
+					utils.Append(&out, signal)
+				}
+			}
+		}
+	}
+	return out
+}
+
+// Returns the key signaled by a send, `Done` call or `close` call, with a nil root for other nodes
+func signalJoin(c *Context, n ast.Node) JoinKey {
+	switch n1 := n.(type) {
+	case *ast.SendStmt:
+		return joinKey(c, n1.Chan)
+	case *ast.CallExpr:
+		if join := methodCallReceiver(c, n1, doneMethods); join.Root != nil {
+			return join
+		}
+		ident, isIdent := ast.Unparen(n1.Fun).(*ast.Ident)
+		if !isIdent || len(n1.Args) != 1 {
+			break
+		}
+		if builtin, isBuiltin := c.p.TypesInfo.Uses[ident].(*types.Builtin); isBuiltin && builtin.Name() == "close" {
+			return joinKey(c, n1.Args[0])
+		}
+	}
+	return JoinKey{Root: nil, Path: ""}
+}
+
+// Returns what the statements of a goroutine's block signal
+func blockSignals(c *Context, b *cfg.Block) []Signal {
+	out := []Signal{}
+	var visit func(n ast.Node) bool
+	visit = func(n ast.Node) bool {
+		switch n1 := n.(type) {
+		case *ast.FuncLit:
+			// Only deferred closures run with the goroutine's statements
+			return false
+		case *ast.DeferStmt:
+			if lit, isLit := ast.Unparen(n1.Call.Fun).(*ast.FuncLit); isLit {
+				ast.Inspect(lit.Body, visit)
+				return false
+			}
+			if join := signalJoin(c, n1.Call); join.Root != nil {
+				// Runs as the goroutine returns
+				utils.Append(&out, Signal{Join: join, Node: nil})
+				return false
+			}
+		}
+		if join := signalJoin(c, n); join.Root != nil {
+			utils.Append(&out, Signal{Join: join, Node: n})
+		}
+		return true
+	}
+	if clause, isComm := b.Stmt.(*ast.CommClause); isComm && b.Kind == cfg.KindSelectCaseBody && clause.Comm != nil {
+		// Only the chosen case's send happens
+		ast.Inspect(clause.Comm, visit)
+	}
+	for _, n := range b.Nodes {
+		if stmt, isStmt := n.(ast.Stmt); isStmt && c.flows.SelectComm(stmt) {
+			continue
+		}
+		ast.Inspect(n, visit)
+	}
+	return out
+}
+
+// Returns the variables initialized in the scope
+func initializedIds(scope *Scope) map[VarId]bool {
+	out := map[VarId]bool{}
+	for vid, decl := range scope.Uninitialized {
+		if len(decl.Uninitialized) == 0 {
+			out[vid] = true
+		}
+	}
+	return out
+}
+
+// Records the variables initialized when the goroutine being started signals at the node
+func RecordSignal(c *Context, n ast.Node) {
+	if _, isSignal := c.signalStates[n]; isSignal {
+		c.signalStates[n] = initializedIds(c.scope)
+	}
+}
+
+// Evaluates a goroutine with the state at the time it's started.  The variables it initializes
+// before each of its signals of a join aren't initialized in the outer flow until it's joined.
+func StartGoroutine(c *Context, lit *ast.FuncLit, start token.Pos, signals []Signal) {
+	outScope, states := EvalClosure(c, lit, signals)
+	exitState := initializedIds(outScope)
+	for vid, decl := range c.scope.Uninitialized {
+		if len(decl.Uninitialized) == 0 {
+			continue
+		}
+		// Whether the variable is initialized at every signal of each join
+		joins := map[JoinKey]bool{}
+		for _, signal := range signals {
+			state := exitState
+			if signal.Node != nil {
+				state = states[signal.Node]
+			}
+			if state == nil {
+				// Not reached
+				continue
+			}
+			initialized, seen := joins[signal.Join]
+			joins[signal.Join] = (initialized || !seen) && state[vid]
+		}
+		for join, initialized := range joins {
+			if initialized {
+				decl.Pending[PendingWrite{Join: join, Start: start}] = true
+			}
+		}
+	}
+}
+
+// Marks the variables initialized by the goroutines the key joins initialized, after waiting for
+// all of them
+func Join(c *Context, join JoinKey) {
+	if join.Root == nil {
+		return
+	}
+	for vid, decl := range c.scope.Uninitialized {
+		for write := range decl.Pending {
+			if write.Join == join {
+				c.scope.MarkIdInitialized(vid)
+				break
+			}
+		}
+	}
+}
+
+// Like Join after receiving from a channel, which only joins one of the goroutines signaling it.
+// It's only known which if they were all started by the same statement, ex: in a loop.
+func JoinOne(c *Context, join JoinKey) {
+	if join.Root == nil {
+		return
+	}
+	starts := map[token.Pos]bool{}
+	for _, decl := range c.scope.Uninitialized {
+		for write := range decl.Pending {
+			if write.Join == join {
+				starts[write.Start] = true
+			}
+		}
+	}
+	if len(starts) == 1 {
+		Join(c, join)
+	}
+}
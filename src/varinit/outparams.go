@@ -0,0 +1,274 @@
+package varinit
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/cfg"
+	"golang.org/x/tools/go/types/typeutil"
+
+	"github.com/upsun/vinego/src/utils"
+)
+
+// Indices of the pointer parameters a function writes through on every path that returns
+type OutParamsFact struct {
+	Params []int
+}
+
+func (*OutParamsFact) AFact() {}
+
+func (f *OutParamsFact) String() string {
+	return fmt.Sprintf("outparams%v", f.Params)
+}
+
+// Functions known to initialize what their parameters point to, by full name (see
+// `types.Func.FullName`).  For variadic parameters all the variadic arguments are written.
+var KnownOutParams = func() map[string][]int {
+	out := map[string][]int{
+		"(*database/sql.Row).Scan":        {0},
+		"(*database/sql.Rows).Scan":       {0},
+		"encoding/json.Unmarshal":         {1},
+		"(*encoding/json.Decoder).Decode": {0},
+		"encoding/xml.Unmarshal":          {1},
+		"(*encoding/xml.Decoder).Decode":  {0},
+		"encoding/binary.Read":            {2},
+		"fmt.Scan":                        {0},
+		"fmt.Scanln":                      {0},
+		"fmt.Scanf":                       {1},
+		"fmt.Sscan":                       {1},
+		"fmt.Sscanln":                     {1},
+		"fmt.Sscanf":                      {2},
+		"fmt.Fscan":                       {1},
+		"fmt.Fscanln":                     {1},
+		"fmt.Fscanf":                      {2},
+	}
+	// Flags are set to their default value immediately
+	for _, flagType := range []string{"Bool", "Duration", "Float64", "Int", "Int64", "String", "Text", "Uint", "Uint64"} {
+		out["flag."+flagType+"Var"] = []int{0}
+		out["(*flag.FlagSet)."+flagType+"Var"] = []int{0}
+	}
+	return out
+}()
+
+// How passing pointers to variables to functions affects their initialization
+//
+//vinego:check allfields
+type OutParams struct {
+	// Returns the indices of the parameters the function writes through, false if unknown
+	Get func(fn *types.Func) ([]int, bool)
+	// If set, taking the address of a variable initializes it unless it's passed to a function known
+	// to not write to it.  Otherwise it's a use of the variable.
+	Permissive bool
+}
+
+// Only knows the functions in KnownOutParams
+func KnownOutParamsOnly(fn *types.Func) ([]int, bool) {
+	params, known := KnownOutParams[fn.Origin().FullName()]
+	return params, known
+}
+
+// Returns the variable, field or pointee written through a pointer argument: `&x`, `&x.a` or a
+// pointer variable `p`
+func WrittenId(c *Context, arg ast.Expr) (VarId, bool) {
+	arg = ast.Unparen(arg)
+	if unary, isUnary := arg.(*ast.UnaryExpr); isUnary && unary.Op == token.AND {
+		if ident, isIdent := ast.Unparen(unary.X).(*ast.Ident); isIdent {
+			return DeclIdForUse(c.p, ident), true
+		}
+		if ident, field, isField := FieldPath(c.p, unary.X); isField && c.scope.TracksFields(c.p, ident) {
+			id := DeclIdForUse(c.p, ident)
+			id.Field = field
+			return id, true
+		}
+		return VarId{Pos: token.NoPos, Field: ""}, false
+	}
+	if ident, isIdent := arg.(*ast.Ident); isIdent {
+		if _, isPointer := c.p.TypesInfo.TypeOf(ident).Underlying().(*types.Pointer); isPointer {
+			id := DeclIdForUse(c.p, ident)
+			id.Field = PointeeField
+			return id, true
+		}
+	}
+	return VarId{Pos: token.NoPos, Field: ""}, false
+}
+
+// Returns the indices of the arguments of the call passed to parameters the callee writes through,
+// and of those it's not known whether the callee writes through: all of them if the callee isn't
+// known (ex: calls of function values or interface methods), otherwise the ones passed to
+// interface typed parameters, which could be written through by reflection (ex: `row.Scan(&x)`).
+func CalleeOutArgs(c *Context, call *ast.CallExpr) (map[int]bool, map[int]bool) {
+	written := map[int]bool{}
+	unknown := map[int]bool{}
+	fn := typeutil.StaticCallee(c.p.TypesInfo, call)
+	params, known := []int{}, false
+	if fn != nil {
+		params, known = c.outParams.Get(fn)
+	}
+	if !known {
+		for i := range call.Args {
+			unknown[i] = true
+		}
+		return written, unknown
+	}
+	// Method expressions take the receiver as the first argument
+	offset := 0
+	if sel, isSel := ast.Unparen(call.Fun).(*ast.SelectorExpr); isSel {
+		if selection := c.p.TypesInfo.Selections[sel]; selection != nil && selection.Kind() == types.MethodExpr {
+			offset = 1
+		}
+	}
+	sig := fn.Signature()
+	for i := offset; i < len(call.Args); i++ {
+		param := i - offset
+		paramType := types.Type(nil)
+		if sig.Variadic() && !call.Ellipsis.IsValid() && param >= sig.Params().Len()-1 {
+			param = sig.Params().Len() - 1
+			paramType = sig.Params().At(param).Type().(*types.Slice).Elem()
+		} else if param < sig.Params().Len() {
+			paramType = sig.Params().At(param).Type()
+		}
+		if slices.Contains(params, param) {
+			written[i] = true
+		} else if paramType != nil && types.IsInterface(paramType) {
+			unknown[i] = true
+		}
+	}
+	return written, unknown
+}
+
+// Determines the out parameters of the functions in the package as they're needed and exports them
+// as facts.  Other packages' functions are looked up in facts, functions without facts don't have
+// out parameters.
+type outParamsScanner struct {
+	p          *analysis.Pass
+	flows      *Flows
+	tracker    *Tracker
+	permissive bool
+	decls      map[*types.Func]*ast.FuncDecl
+	// Functions being scanned are present with no out parameters, to stop recursion
+	scanned map[*types.Func][]int
+}
+
+func newOutParamsScanner(p *analysis.Pass, flows *Flows, tracker *Tracker, permissive bool) *outParamsScanner {
+	decls := map[*types.Func]*ast.FuncDecl{}
+	for _, file := range p.Files {
+		for _, decl := range file.Decls {
+			funcDecl, isFuncDecl := decl.(*ast.FuncDecl)
+			if !isFuncDecl {
+				continue
+			}
+			if fn, isFunc := p.TypesInfo.Defs[funcDecl.Name].(*types.Func); isFunc {
+				decls[fn] = funcDecl
+			}
+		}
+	}
+	return &outParamsScanner{
+		p:          p,
+		flows:      flows,
+		tracker:    tracker,
+		permissive: permissive,
+		decls:      decls,
+		scanned:    map[*types.Func][]int{},
+	}
+}
+
+func (s *outParamsScanner) Get(fn *types.Func) ([]int, bool) {
+	fn = fn.Origin()
+	if params, known := KnownOutParams[fn.FullName()]; known {
+		return params, true
+	}
+	if fn.Pkg() != s.p.Pkg {
+		// Facts are only exported for functions with out parameters
+		fact := new(OutParamsFact)
+		s.p.ImportObjectFact(fn, fact)
+		return fact.Params, true
+	}
+	if params, scanned := s.scanned[fn]; scanned {
+		return params, true
+	}
+	decl := s.decls[fn]
+	if decl == nil {
+		return nil, false
+	}
+	flow := s.flows.FuncDecl(decl)
+	if flow == nil {
+		// No body, ex: implemented in assembly
+		return nil, false
+	}
+	s.scanned[fn] = []int{}
+	params := s.scan(fn, decl, flow)
+	s.scanned[fn] = params
+	if len(params) > 0 {
+		s.p.ExportObjectFact(fn, &OutParamsFact{Params: params})
+	}
+	return params, true
+}
+
+// Evaluates the function with what its pointer parameters point to starting uninitialized, and
+// returns the parameters where it's initialized at the end
+func (s *outParamsScanner) scan(fn *types.Func, decl *ast.FuncDecl, flow *cfg.CFG) []int {
+	a := NewAnalysis(s.p, s.flows, s.tracker, &OutParams{
+		Get:        s.Get,
+		Permissive: s.permissive,
+	})
+	a.silent = true
+	entry := &Scope{
+		Location:      BranchId(decl.Body.Pos()),
+		Comment:       "entry",
+		Uninitialized: map[VarId]*Decl{},
+	}
+	params := fn.Signature().Params()
+	tracked := map[int]bool{}
+	for i := 0; i < params.Len(); i++ {
+		param := params.At(i)
+		pointer, isPointer := param.Type().Underlying().(*types.Pointer)
+		if !isPointer || param.Name() == "" || param.Name() == "_" {
+			continue
+		}
+		// Track what's pointed to like a variable of that type
+		nested, track := s.tracker.Fields(s.p, types.NewVar(param.Pos(), param.Pkg(), param.Name(), pointer.Elem()))
+		if !track {
+			continue
+		}
+		fields := []string{PointeeField}
+		if len(nested) > 0 {
+			fields = []string{}
+			for _, field := range nested {
+				utils.Append(&fields, PointeeField+"."+field)
+			}
+		}
+		for _, field := range fields {
+			entry.Uninitialized[VarId{Pos: param.Pos(), Field: field}] = &Decl{
+				Name:          param.Name(),
+				Changed:       false,
+				Uninitialized: map[BranchId]DeclBranch{entry.Location: {Comment: entry.Comment}},
+				Pending:       map[PendingWrite]bool{},
+			}
+		}
+		tracked[i] = true
+	}
+	if len(tracked) == 0 {
+		return []int{}
+	}
+	exit := EvalFunc(a, flow, decl.Type, []*Scope{entry})
+	out := []int{}
+	for i := 0; i < params.Len(); i++ {
+		if !tracked[i] {
+			continue
+		}
+		written := true
+		for id, paramDecl := range exit.Uninitialized {
+			if id.Pos == params.At(i).Pos() && len(paramDecl.Uninitialized) > 0 {
+				written = false
+			}
+		}
+		if written {
+			utils.Append(&out, i)
+		}
+	}
+	return out
+}
@@ -0,0 +1,22 @@
+// Stand-in for golang.org/x/sync/errgroup
+package errgroup
+
+import "sync"
+
+type Group struct {
+	wg  sync.WaitGroup
+	err error
+}
+
+func (g *Group) Go(f func() error) {
+	g.wg.Go(func() {
+		if err := f(); err != nil {
+			g.err = err
+		}
+	})
+}
+
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	return g.err
+}
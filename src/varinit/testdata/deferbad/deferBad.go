@@ -0,0 +1,61 @@
+package deferbad
+
+import (
+	"runtime"
+	"testing"
+)
+
+func produce() int  { return 7 }
+func consume(x any) {}
+
+func main() {
+	var res int
+	defer func() {
+		consume(res) // want "`res` hasn't been initialized"
+	}()
+	if produce() > 3 {
+		return
+	}
+	res = 1
+}
+
+func panics() {
+	var res int
+	defer func() {
+		consume(res) // want "`res` hasn't been initialized"
+	}()
+	if produce() > 3 {
+		panic("bad")
+	}
+	res = 1
+}
+
+// Deferred functions run when the goroutine exits
+func goexit() {
+	var res int
+	defer func() {
+		consume(res) // want "`res` hasn't been initialized"
+	}()
+	if produce() > 3 {
+		runtime.Goexit()
+	}
+	res = 1
+}
+
+func fatal(t *testing.T) {
+	var res int
+	defer func() {
+		consume(res) // want "`res` hasn't been initialized"
+	}()
+	if produce() > 3 {
+		t.Fatal("bad")
+	}
+	res = 1
+}
+
+// Arguments are evaluated when deferring
+func args() {
+	var res int
+	defer consume(res) // want "`res` hasn't been initialized"
+	res = 1
+}
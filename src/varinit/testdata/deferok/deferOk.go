@@ -0,0 +1,63 @@
+package deferok
+
+import (
+	"log"
+	"os"
+)
+
+func produce() int  { return 7 }
+func consume(x any) {}
+
+func main() {
+	var res int
+	defer func() {
+		consume(res)
+	}()
+	res = produce()
+}
+
+func panics() {
+	var res int
+	defer func() {
+		consume(res)
+	}()
+	if produce() > 3 {
+		res = 1
+		panic("bad")
+	}
+	res = 2
+}
+
+// Deferred functions don't run when the process exits
+func exits() {
+	var res int
+	defer func() {
+		consume(res)
+	}()
+	if produce() > 3 {
+		os.Exit(1)
+	}
+	if produce() > 4 {
+		log.Fatal("bad")
+	}
+	res = 2
+}
+
+// The closure isn't deferred on the early return
+func earlyReturn() {
+	var res int
+	if produce() > 3 {
+		return
+	}
+	res = 1
+	defer func() {
+		consume(res)
+	}()
+}
+
+func namedResult() (res int) {
+	defer func() {
+		consume(res)
+	}()
+	return 4
+}
@@ -0,0 +1,25 @@
+package globalsbad
+
+func produce() int  { return 7 }
+func consume(x any) {}
+
+var never int // want "This variable was never explicitly initialized, by its declaration or on every path of the init functions:\n - .*a.go:14:6\n - .*b.go:14:6\n"
+
+var sometimes int // want "This variable was never explicitly initialized"
+
+var readEarly int
+
+var derived = readEarly + 1 // want "`readEarly` hasn't been initialized"
+
+func init() {
+	if produce() > 3 {
+		sometimes = 1
+	}
+	readEarly = 2
+}
+
+func main() {
+	consume(never)
+	consume(sometimes)
+	consume(derived)
+}
@@ -0,0 +1,18 @@
+package globalsbad
+
+type Point struct {
+	X int
+	Y int
+}
+
+var readInInit int
+
+// Reported once for all fields
+var point Point // want "This variable was never explicitly initialized"
+
+// Runs after the init function in a.go
+func init() {
+	consume(readInInit) // want "`readInInit` hasn't been initialized"
+	readInInit = 1
+	point.X = readInInit
+}
@@ -0,0 +1,11 @@
+package globalsnoinitbad
+
+var x int // want "^This variable was never explicitly initialized$"
+
+var y, z = 1, 2
+
+func consume(x any) {}
+
+func main() {
+	consume(x + y + z)
+}
@@ -0,0 +1,53 @@
+package globalsok
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+func produce() int  { return 7 }
+func consume(x any) {}
+
+type Config struct {
+	Name string
+}
+
+var config Config
+
+var _ = json.Unmarshal([]byte(`{"Name": "a"}`), &config)
+
+// Initializers run in dependency order: `ready` first, since `described` uses it
+var described = ready && settings.Name != ""
+var ready = json.Unmarshal([]byte(`{"Name": "b"}`), &settings) == nil
+var settings Config
+
+var table map[string]int
+var count int
+
+// Zero value ready, or not tracked
+var mu sync.Mutex
+var items []string
+
+var (
+	first  = produce()
+	second = first + 1
+)
+
+func init() {
+	table = map[string]int{}
+	if produce() > 3 {
+		count = 1
+	} else {
+		count = 2
+	}
+}
+
+func main() {
+	mu.Lock()
+	defer mu.Unlock()
+	consume(config)
+	consume(table)
+	consume(items)
+	consume(second)
+	consume(described)
+}
@@ -0,0 +1,16 @@
+package globalsok
+
+type Point struct {
+	X int
+	Y int
+}
+
+var later int
+var point Point
+
+// Runs after the init function in a.go
+func init() {
+	later = count + 1
+	point.X = 1
+	point.Y = later
+}
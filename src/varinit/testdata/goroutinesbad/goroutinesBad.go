@@ -0,0 +1,150 @@
+package goroutinesbad
+
+import (
+	"sync"
+)
+
+func produce() int  { return 7 }
+func consume(x any) {}
+
+func readBeforeWait() {
+	var a int
+	var wg sync.WaitGroup
+	wg.Go(func() { a = produce() })
+	consume(a) // want "`a` is read before waiting for the goroutines initializing it, this is likely a race"
+	wg.Wait()
+}
+
+func notJoined() {
+	var b int
+	go func() { b = produce() }()
+	consume(b) // want "`b` hasn't been initialized"
+}
+
+func readInGoroutine() {
+	var c int
+	var wg sync.WaitGroup
+	wg.Go(func() { consume(c) }) // want "`c` hasn't been initialized"
+	wg.Wait()
+	c = produce()
+}
+
+func sometimesStarted() {
+	var d int
+	var wg sync.WaitGroup
+	if produce() > 3 {
+		wg.Go(func() { d = produce() })
+	}
+	wg.Wait()
+	consume(d) // want "`d` hasn't been initialized"
+}
+
+func sometimesWritten() {
+	var e int
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		if produce() > 3 {
+			e = produce()
+		}
+	})
+	wg.Wait()
+	consume(e) // want "`e` hasn't been initialized"
+}
+
+func otherGroup() {
+	var f int
+	var wg1, wg2 sync.WaitGroup
+	wg1.Go(func() { f = produce() })
+	wg2.Wait()
+	consume(f) // want "`f` is read before waiting for the goroutines initializing it, this is likely a race"
+	wg1.Wait()
+}
+
+// One receive only joins one of the goroutines
+func twoSenders() {
+	var g, h int
+	results := make(chan bool)
+	go func() {
+		g = produce()
+		results <- true
+	}()
+	go func() {
+		h = produce()
+		results <- true
+	}()
+	<-results
+	consume(g) // want "`g` is read before waiting for the goroutines initializing it, this is likely a race"
+	<-results
+	consume(h) // want "`h` is read before waiting for the goroutines initializing it, this is likely a race"
+}
+
+// The goroutine doesn't signal on every path
+func sometimesDone() {
+	var i int
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		i = produce()
+		if produce() > 3 {
+			wg.Done()
+		}
+	}()
+	wg.Wait()
+	consume(i) // want "`i` hasn't been initialized"
+}
+
+func sometimesSent() {
+	var j int
+	done := make(chan bool)
+	go func() {
+		j = produce()
+		select {
+		case done <- true:
+		default:
+		}
+	}()
+	<-done
+	consume(j) // want "`j` hasn't been initialized"
+}
+
+// Signaled before writing
+func doneBeforeWrite() {
+	var k int
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		wg.Done()
+		k = produce()
+	}()
+	wg.Wait()
+	consume(k) // want "`k` hasn't been initialized"
+}
+
+func sentBeforeWrite() {
+	var l int
+	done := make(chan bool)
+	go func() {
+		done <- true
+		l = produce()
+	}()
+	<-done
+	consume(l) // want "`l` hasn't been initialized"
+}
+
+type worker struct {
+	wg sync.WaitGroup
+}
+
+// The same field of another variable
+func otherReceiver() {
+	var m int
+	var a, b worker
+	b.wg.Add(1)
+	go func() {
+		m = produce()
+		b.wg.Done()
+	}()
+	a.wg.Wait()
+	consume(m) // want "`m` is read before waiting for the goroutines initializing it, this is likely a race"
+	b.wg.Wait()
+}
@@ -0,0 +1,113 @@
+package goroutinesok
+
+import (
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+func produce() int  { return 7 }
+func consume(x any) {}
+
+func waitGroupGo() {
+	var a, b int
+	var wg sync.WaitGroup
+	wg.Go(func() { a = produce() })
+	wg.Go(func() { b = produce() })
+	wg.Wait()
+	consume(a + b)
+}
+
+func waitGroupDone() {
+	var c int
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		c = produce()
+	}()
+	wg.Wait()
+	consume(c)
+}
+
+func doneChannel() {
+	var d int
+	done := make(chan struct{})
+	go func() {
+		d = produce()
+		close(done)
+	}()
+	<-done
+	consume(d)
+}
+
+func resultChannel() {
+	var e int
+	results := make(chan bool)
+	go func() {
+		e = produce()
+		results <- true
+	}()
+	consume(<-results)
+	consume(e)
+}
+
+func errGroup() error {
+	var f int
+	var g errgroup.Group
+	g.Go(func() error {
+		f = produce()
+		return nil
+	})
+	if err := g.Wait(); err != nil {
+		return err
+	}
+	consume(f)
+	return nil
+}
+
+func deferredClose() {
+	var h int
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if produce() > 3 {
+			h = 1
+			return
+		}
+		h = 2
+	}()
+	<-done
+	consume(h)
+}
+
+func sentOnAllPaths() {
+	var i int
+	results := make(chan int)
+	go func() {
+		i = produce()
+		if i > 3 {
+			results <- 1
+		} else {
+			results <- 2
+		}
+	}()
+	consume(<-results)
+	consume(i)
+}
+
+type worker struct {
+	wg sync.WaitGroup
+}
+
+func fieldWaitGroup() {
+	var k int
+	var w worker
+	w.wg.Add(1)
+	go func() {
+		k = produce()
+		w.wg.Done()
+	}()
+	w.wg.Wait()
+	consume(k)
+}
@@ -0,0 +1,39 @@
+package gotobad
+
+func produce() int  { return 7 }
+func consume(x int) {}
+
+func skipsAssignment() {
+	var x int
+	if produce() > 3 {
+		goto End
+	}
+	x = 1
+End:
+	consume(x) // want "`x` hasn't been initialized"
+}
+
+func outOfBlock() {
+	var x int
+	for i := 0; i < 3; i++ {
+		if produce() > 3 {
+			goto Done
+		}
+		x = 1
+	}
+	x = 2
+Done:
+	consume(x) // want "`x` hasn't been initialized"
+}
+
+func backwards() {
+	var x int
+	var y int
+Again:
+	if produce() > 3 {
+		consume(y) // want "`y` hasn't been initialized"
+	}
+	y = x // want "`x` hasn't been initialized"
+	x = 1
+	goto Again
+}
@@ -0,0 +1,36 @@
+package gotook
+
+func produce() int  { return 7 }
+func consume(x int) {}
+
+func retry() {
+	var x int
+Retry:
+	x = produce()
+	if x < 3 {
+		goto Retry
+	}
+	consume(x)
+}
+
+func outOfBlock() {
+	var x int
+	for {
+		if produce() > 3 {
+			x = 1
+			goto Done
+		}
+	}
+Done:
+	consume(x)
+}
+
+func skipsUse() {
+	var x int
+	if produce() > 3 {
+		goto End
+	}
+	x = 1
+	consume(x)
+End:
+}
@@ -0,0 +1,31 @@
+package initializerbad
+
+type Config struct {
+	Name string
+	Size int
+}
+
+//vinego:check initializer
+func (c *Config) Defaults() { // want Defaults:"checks\\(initializer\\)"
+	c.Name = "default"
+	c.Size = 1
+}
+
+func (c *Config) Describe() string {
+	return c.Name
+}
+
+func produce() int  { return 7 }
+func consume(x any) {}
+
+func main() {
+	var c Config
+	consume(c.Describe()) // want "`c.Name` hasn't been initialized"
+	c.Defaults()
+
+	var d Config
+	if produce() > 3 {
+		d.Defaults()
+	}
+	consume(d) // want "`d.Name` hasn't been initialized"
+}
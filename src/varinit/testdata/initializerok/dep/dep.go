@@ -0,0 +1,14 @@
+package dep
+
+type Client struct {
+	addr string
+}
+
+//vinego:check initializer
+func (c *Client) Init(addr string) {
+	c.addr = addr
+}
+
+func (c *Client) Addr() string {
+	return c.addr
+}
@@ -0,0 +1,39 @@
+package initializerok
+
+import "initializerok/dep"
+
+type Config struct {
+	Name string
+	Size int
+}
+
+//vinego:check initializer
+func (c *Config) Defaults() { // want Defaults:"checks\\(initializer\\)"
+	c.Name = "default"
+	c.Size = 1
+}
+
+type Box[T any] struct {
+	Value T
+}
+
+//vinego:check initializer
+func (b *Box[T]) Reset() { // want Reset:"checks\\(initializer\\)"
+	b.Value = *new(T)
+}
+
+func consume(x any) {}
+
+func main() {
+	var c Config
+	c.Defaults()
+	consume(c)
+
+	var client dep.Client
+	client.Init("localhost")
+	consume(client.Addr())
+
+	var b Box[int]
+	b.Reset()
+	consume(b)
+}
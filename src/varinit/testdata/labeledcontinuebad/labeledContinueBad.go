@@ -0,0 +1,34 @@
+package labeledcontinuebad
+
+func produce() int  { return 7 }
+func consume(x int) {}
+
+func carried() {
+	var x int
+Outer:
+	for i := 0; i < 3; i++ {
+		consume(x) // want "`x` hasn't been initialized"
+		for {
+			if produce() > 3 {
+				x = 1
+				continue Outer
+			}
+			break
+		}
+	}
+}
+
+func skipsAssignment() {
+	var x int
+Outer:
+	for i := 0; i < 3; i++ {
+		for {
+			if produce() > 3 {
+				continue Outer
+			}
+			x = 1
+			break
+		}
+	}
+	consume(x) // want "`x` hasn't been initialized"
+}
@@ -0,0 +1,36 @@
+package labeledcontinueok
+
+func produce() int  { return 7 }
+func consume(x int) {}
+
+func main() {
+	var x int
+	x = 0
+Outer:
+	for i := 0; i < 3; i++ {
+		for {
+			if produce() > 3 {
+				x = 1
+				continue Outer
+			}
+			consume(x)
+			break
+		}
+	}
+	consume(x)
+}
+
+func assignedBeforeContinue() {
+Outer:
+	for i := 0; i < 3; i++ {
+		var y int
+		for {
+			if produce() > 3 {
+				continue Outer
+			}
+			y = 1
+			break
+		}
+		consume(y)
+	}
+}
@@ -0,0 +1,10 @@
+package loopbodyusebad
+
+func consume(x int) {}
+
+func main() {
+	var x int
+	for range 3 {
+		consume(x) // want "x"
+	}
+}
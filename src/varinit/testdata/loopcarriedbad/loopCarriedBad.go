@@ -0,0 +1,12 @@
+package loopcarriedbad
+
+func consume(x int) {}
+
+func main() {
+	var prev int
+	for i := 0; i < 3; i++ {
+		// Not initialized on the first iteration
+		consume(prev) // want "prev"
+		prev = i
+	}
+}
@@ -0,0 +1,32 @@
+package loopcarriedok
+
+func produce() int  { return 7 }
+func consume(x int) {}
+
+func main() {
+	var x int
+	for {
+		x = produce()
+		if x > 3 {
+			break
+		}
+	}
+	consume(x)
+
+	var prev int
+	prev = 0
+	for i := 0; i < 3; i++ {
+		consume(prev)
+		prev = i
+	}
+
+	var y int
+	for i := 0; ; i++ {
+		if i > 0 {
+			continue
+		}
+		y = i
+		break
+	}
+	consume(y)
+}
@@ -0,0 +1,14 @@
+package methodcallbad
+
+type T struct {
+	x int
+}
+
+func (t T) Get() int { return t.x }
+
+func main() {
+	var t T
+	t.Get() // want "t"
+	var f func()
+	f() // want "f"
+}
@@ -0,0 +1,53 @@
+package nestedclosuresok
+
+func produce() int  { return 7 }
+func consume(x any) {}
+func call(f func()) { f() }
+
+// Each closure is evaluated on every iteration of the enclosing loops, which shouldn't multiply with
+// the nesting depth
+func deeplyNested() {
+	var a int
+	for i := 0; i < 3; i++ {
+		call(func() {
+			for i := 0; i < 3; i++ {
+				call(func() {
+					for i := 0; i < 3; i++ {
+						call(func() {
+							for i := 0; i < 3; i++ {
+								call(func() {
+									for i := 0; i < 3; i++ {
+										call(func() {
+											for i := 0; i < 3; i++ {
+												call(func() {
+													for i := 0; i < 3; i++ {
+														call(func() {
+															for i := 0; i < 3; i++ {
+																call(func() {
+																	for i := 0; i < 3; i++ {
+																		call(func() {
+																			for i := 0; i < 3; i++ {
+																				call(func() {
+																					a = produce()
+																					consume(a)
+																				})
+																			}
+																		})
+																	}
+																})
+															}
+														})
+													}
+												})
+											}
+										})
+									}
+								})
+							}
+						})
+					}
+				})
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package noreturnbad
+
+import "os"
+
+var exit = os.Exit
+
+// Not known to never return
+func die(msg string) {
+	exit(1)
+}
+
+func produce() int  { return 7 }
+func consume(x any) {}
+
+func main() {
+	var a int
+	if produce() > 3 {
+		die("bad")
+	} else {
+		a = 1
+	}
+	consume(a) // want "`a` hasn't been initialized"
+}
@@ -0,0 +1,90 @@
+package noreturnok
+
+import (
+	"log"
+	"os"
+	"testing"
+)
+
+var exit = os.Exit
+
+//vinego:check noreturn
+func die(msg string) { // want die:"checks\\(noreturn\\)"
+	exit(1)
+}
+
+type Dier interface {
+	//vinego:check noreturn
+	Die(msg string) // want Die:"checks\\(noreturn\\)"
+}
+
+func produce() int  { return 7 }
+func consume(x any) {}
+
+func load(x *int) { // want load:"outparams\\[0\\]"
+	if produce() > 3 {
+		*x = 1
+		return
+	}
+	log.Fatal("can't load")
+}
+
+func main() {
+	var a int
+	if produce() > 3 {
+		log.Fatal("bad")
+	} else {
+		a = 1
+	}
+	consume(a)
+
+	var b int
+	if produce() > 3 {
+		os.Exit(1)
+	}
+	b = 2
+	consume(b)
+
+	var c int
+	switch produce() {
+	case 1:
+		c = 1
+	case 2:
+		c = 2
+	default:
+		panic("unexpected")
+	}
+	consume(c)
+
+	var d int
+	if produce() > 3 {
+		die("bad")
+	} else {
+		d = 1
+	}
+	consume(d)
+
+	var e int
+	load(&e)
+	consume(e)
+}
+
+func withDier(dier Dier) {
+	var a int
+	if produce() > 3 {
+		dier.Die("bad")
+	} else {
+		a = 1
+	}
+	consume(a)
+}
+
+func withT(t *testing.T) {
+	var a int
+	if produce() > 3 {
+		t.Fatal("bad")
+	} else {
+		a = 1
+	}
+	consume(a)
+}
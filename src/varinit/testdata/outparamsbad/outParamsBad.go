@@ -0,0 +1,43 @@
+package outparamsbad
+
+import "fmt"
+
+func produce() int  { return 7 }
+func consume(x any) {}
+
+func initSome(x *int) {
+	if produce() > 3 {
+		*x = 1
+	}
+}
+
+func ignore(x *int) {}
+
+func load(v any) {}
+
+func main() {
+	var a int
+	fmt.Println(&a) // want "`a` hasn't been initialized"
+
+	var b int
+	initSome(&b) // want "`b` hasn't been initialized"
+	consume(b)
+
+	var c int
+	ignore(&c) // want "`c` hasn't been initialized"
+
+	// Unknown functions don't initialize variables unless permissive_address_of is set
+	var d int
+	f := initSome
+	f(&d) // want "`d` hasn't been initialized"
+
+	// Values passed as interfaces could be written by reflection, the same
+	var g int
+	load(&g) // want "`g` hasn't been initialized"
+
+	// Taking the address isn't a use, but doesn't initialize either
+	var e int
+	p := &e
+	consume(p)
+	consume(e) // want "`e` hasn't been initialized"
+}
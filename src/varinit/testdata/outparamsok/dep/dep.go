@@ -0,0 +1,5 @@
+package dep
+
+func Load(out *string) {
+	*out = "loaded"
+}
@@ -0,0 +1,82 @@
+package outparamsok
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/binary"
+	"encoding/json"
+	"flag"
+	"fmt"
+
+	"outparamsok/dep"
+)
+
+type Config struct {
+	Name string
+	Size int
+}
+
+func produce() int  { return 7 }
+func consume(x any) {}
+
+func initBoth(x *int) { // want initBoth:"outparams\\[0\\]"
+	if produce() > 3 {
+		*x = 1
+	} else {
+		*x = 2
+	}
+}
+
+func forward(x *int) { // want forward:"outparams\\[0\\]"
+	initBoth(x)
+}
+
+func decode(data []byte, c *Config) error { // want decode:"outparams\\[1\\]"
+	return json.Unmarshal(data, c)
+}
+
+func fill(c *Config) { // want fill:"outparams\\[0\\]"
+	c.Name = "a"
+	initBoth(&c.Size)
+}
+
+func main(db *sql.DB) {
+	var a int
+	initBoth(&a)
+	consume(a)
+
+	var b int
+	forward(&b)
+	consume(b)
+
+	var c Config
+	_ = decode([]byte("{}"), &c)
+	consume(c)
+
+	var d Config
+	fill(&d)
+	consume(d)
+
+	var e, f int
+	_, _ = fmt.Sscan("1 2", &e, &f)
+	consume(e + f)
+
+	var name string
+	flag.StringVar(&name, "name", "", "")
+	consume(name)
+
+	var size uint32
+	_ = binary.Read(bytes.NewReader(nil), binary.LittleEndian, &size)
+	consume(size)
+
+	var loaded string
+	dep.Load(&loaded)
+	consume(loaded)
+
+	var id, count int
+	row := db.QueryRow("")
+	_ = row.Scan(&id)
+	rows, _ := db.Query("")
+	_ = rows.Scan(&count)
+	consume(id + count)
+}
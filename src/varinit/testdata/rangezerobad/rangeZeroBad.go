@@ -0,0 +1,12 @@
+package rangezerobad
+
+func produce() []int { return []int{} }
+func consume(x int)  {}
+
+func main() {
+	var x int
+	for _, v := range produce() {
+		x = v
+	}
+	consume(x) // want "x"
+}
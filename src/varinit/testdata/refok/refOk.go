@@ -2,7 +2,9 @@ package refok
 
 func consume(i int) {}
 
-func outvarinit(i *int) {}
+func outvarinit(i *int) { // want outvarinit:"outparams\\[0\\]"
+	*i = 4
+}
 
 func main() {
 	var x int
@@ -0,0 +1,56 @@
+package selectbad
+
+func produce() int  { return 7 }
+func consume(x any) {}
+
+func otherCase(ch chan int, quit chan bool) {
+	var x int
+	select {
+	case x = <-ch:
+	case <-quit:
+	}
+	consume(x) // want "`x` hasn't been initialized"
+}
+
+func defaultCase(ch chan int) {
+	var x int
+	var ok bool
+	select {
+	case x, ok = <-ch:
+	default:
+		x = 0
+	}
+	consume(x)
+	consume(ok) // want "`ok` hasn't been initialized"
+}
+
+func sent(ch chan int) {
+	var x int
+	select {
+	case ch <- x: // want "`x` hasn't been initialized"
+	default:
+	}
+}
+
+func notJoined(timeout chan bool) {
+	var x int
+	done := make(chan struct{})
+	go func() {
+		x = produce()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-timeout:
+	}
+	consume(x) // want "`x` hasn't been initialized"
+}
+
+func index(ch chan int) {
+	var i int
+	values := map[int]int{}
+	select {
+	case values[i] = <-ch: // want "`i` hasn't been initialized"
+	default:
+	}
+}
@@ -0,0 +1,60 @@
+package selectok
+
+func produce() int  { return 7 }
+func consume(x any) {}
+
+func received(ch chan int, quit chan bool) {
+	var x int
+	select {
+	case x = <-ch:
+	case <-quit:
+		x = 0
+	}
+	consume(x)
+}
+
+func receivedOk(ch chan int) {
+	var x int
+	var ok bool
+	select {
+	case x, ok = <-ch:
+	default:
+		x, ok = 0, false
+	}
+	consume(x)
+	consume(ok)
+}
+
+func defined(ch chan int, quit chan bool) {
+	select {
+	case v := <-ch:
+		consume(v)
+	case v, ok := <-ch:
+		consume(v)
+		consume(ok)
+	case <-quit:
+	}
+}
+
+func sent(ch chan int) {
+	var x int
+	x = produce()
+	select {
+	case ch <- x:
+	default:
+	}
+}
+
+func joined(timeout chan bool) {
+	var x int
+	done := make(chan struct{})
+	go func() {
+		x = produce()
+		close(done)
+	}()
+	select {
+	case <-done:
+		consume(x)
+	case <-timeout:
+	}
+}
@@ -0,0 +1,23 @@
+package noreturnsettingsok
+
+import "os"
+
+var exit = os.Exit
+
+// Listed in the settings
+func fail(msg string) {
+	exit(1)
+}
+
+func produce() int  { return 7 }
+func consume(x any) {}
+
+func main() {
+	var a int
+	if produce() > 3 {
+		fail("bad")
+	} else {
+		a = 1
+	}
+	consume(a)
+}
@@ -0,0 +1,39 @@
+package permissiveok
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+func consume(x any) {}
+
+func ignore(x *int) {}
+
+func load(v any) {}
+
+func main(db *sql.DB) {
+	var a int
+	f := ignore
+	f(&a)
+	consume(a)
+
+	var b int
+	p := &b
+	consume(p)
+	consume(b)
+
+	// Values passed as interfaces could be written by reflection
+	var d int
+	load(&d)
+	consume(d)
+
+	var e, g int
+	row := db.QueryRow("")
+	_ = row.Scan(&e)
+	_, _ = fmt.Sscan("1", &g)
+	consume(e + g)
+
+	// Functions known to not write through the pointer still don't initialize
+	var c int
+	ignore(&c) // want "`c` hasn't been initialized"
+}
@@ -0,0 +1,18 @@
+package zerooksettingsok
+
+// Listed in the settings
+type Registry struct {
+	Names []string
+}
+
+func (r *Registry) Add(name string) {
+	r.Names = append(r.Names, name)
+}
+
+func consume(x any) {}
+
+func main() {
+	var r Registry
+	r.Add("a")
+	consume(r)
+}
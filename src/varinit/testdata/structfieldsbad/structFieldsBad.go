@@ -0,0 +1,37 @@
+package structfieldsbad
+
+type Point struct {
+	X int
+	Y int
+}
+
+type Rect struct {
+	Min Point
+	Max Point
+}
+
+func produce() int  { return 7 }
+func consume(x any) {}
+
+func main() {
+	var p Point
+	p.X = 1
+	if produce() > 3 {
+		p.Y = 2
+	}
+	consume(p) // want "`p.Y` hasn't been initialized in the following branches:\n - .*structFieldsBad.go:16:13\n"
+
+	var q Point
+	q.X = 1
+	consume(q.Y) // want "`q.Y` hasn't been initialized"
+
+	var r Rect
+	r.Min.X = 1
+	r.Max = Point{X: 1, Y: 2}
+	consume(r) // want "`r.Min.Y` hasn't been initialized"
+
+	var s Point
+	s.X += 1 // want "`s.X` hasn't been initialized"
+	s.Y = 1
+	consume(s)
+}
@@ -0,0 +1,70 @@
+package structfieldsok
+
+import "time"
+
+type Point struct {
+	X int
+	Y int
+}
+
+type Rect struct {
+	Min Point
+	Max Point
+}
+
+type Labeled struct {
+	Point
+	Label string
+}
+
+func produce() int  { return 7 }
+func consume(x any) {}
+
+func set(x *int) { // want set:"outparams\\[0\\]"
+	*x = 1
+}
+
+func setPoint(p *Point) { // want setPoint:"outparams\\[0\\]"
+	p.X = 1
+	p.Y = 2
+}
+
+func main() {
+	var p Point
+	p.X = 1
+	p.Y = 2
+	consume(p)
+
+	var q Point
+	q.X = 1
+	// Reading an initialized field is fine before the rest are initialized
+	consume(q.X)
+	set(&q.Y)
+	consume(q)
+
+	var r Rect
+	r.Min = Point{X: 0, Y: 0}
+	r.Max.X = 1
+	if produce() > 3 {
+		r.Max.Y = 2
+	} else {
+		setPoint(&r.Max)
+	}
+	consume(r)
+
+	// Promoted fields are tracked under their embedded field
+	var l Labeled
+	l.X = 1
+	l.Point.Y = 2
+	l.Label = "a"
+	consume(l)
+
+	// Structs with fields that can't be assigned here are tracked as a whole
+	var t time.Time
+	setTime(&t)
+	consume(t)
+}
+
+func setTime(t *time.Time) { // want setTime:"outparams\\[0\\]"
+	*t = time.Now()
+}
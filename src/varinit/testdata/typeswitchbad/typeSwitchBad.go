@@ -0,0 +1,32 @@
+package typeswitchbad
+
+func produce() any  { return 7 }
+func consume(x any) {}
+
+func guardUninitialized() {
+	var x any
+	switch t := x.(type) { // want "`x` hasn't been initialized"
+	case int:
+		consume(t)
+	}
+}
+
+func noBindingUninitialized() {
+	var x any
+	switch x.(type) { // want "`x` hasn't been initialized"
+	case int:
+	}
+}
+
+func missingClause() {
+	var out string
+	switch t := produce().(type) {
+	case int:
+		out = "int"
+	case string:
+		consume(t)
+	default:
+		out = "other"
+	}
+	consume(out) // want "`out` hasn't been initialized"
+}
@@ -0,0 +1,38 @@
+package typeswitchok
+
+func produce() any  { return 7 }
+func consume(x any) {}
+
+func bindings() {
+	var x any
+	x = produce()
+	switch t := x.(type) {
+	case int:
+		consume(t + 1)
+	case string, bool:
+		consume(t)
+	default:
+		consume(t)
+	}
+}
+
+func assignedInClauses() {
+	var out string
+	switch t := produce().(type) {
+	case int:
+		out = "int"
+	case string:
+		out = t
+	default:
+		out = "other"
+	}
+	consume(out)
+}
+
+func noBinding() {
+	var x any
+	x = produce()
+	switch x.(type) {
+	case int:
+	}
+}
@@ -0,0 +1,27 @@
+package zerookbad
+
+import "sync"
+
+type Client struct {
+	Addr string
+}
+
+func (c *Client) Connect() {}
+
+type Guarded struct {
+	sync.Mutex
+	Value int
+}
+
+func consume(x any) {}
+
+func main() {
+	// Not ready to use without initialization
+	var d Client
+	d.Connect() // want "`d.Addr` hasn't been initialized"
+
+	// Only the zero value ready fields can be left out
+	var g Guarded
+	g.Lock()
+	consume(g.Value) // want "`g.Value` hasn't been initialized"
+}
@@ -0,0 +1,95 @@
+package zerookok
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+//vinego:check zerook
+type Counter struct { // want Counter:"checks\\(zerook\\)"
+	Count int
+}
+
+func (c *Counter) Inc() {
+	c.Count += 1
+}
+
+func (c Counter) Get() int {
+	return c.Count
+}
+
+type Guarded struct {
+	sync.Mutex
+	Value int
+}
+
+type Locks struct {
+	Read  sync.Mutex
+	Write sync.Mutex
+}
+
+func consume(x any) {}
+
+func worker(wg *sync.WaitGroup) {
+	wg.Done()
+}
+
+func main(r io.Reader) {
+	var mu sync.Mutex
+	mu.Lock()
+	mu.Unlock()
+
+	var b strings.Builder
+	b.WriteString("a")
+	consume(b.String())
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	wg.Wait()
+
+	var n atomic.Int64
+	n.Add(1)
+	consume(n.Load())
+
+	var s []int
+	s = append(s, 1)
+	consume(s)
+
+	var c Counter
+	c.Inc()
+	consume(c)
+
+	// Promoted methods initialize the embedded field
+	var g Guarded
+	g.Lock()
+	g.Value = 1
+	consume(&g)
+
+	// Zero value ready types aren't tracked, whatever they're used for
+	var copied strings.Builder
+	consume(copied)
+
+	var value Counter
+	consume(value.Get())
+
+	var buf bytes.Buffer
+	json.NewEncoder(&buf)
+	io.Copy(&buf, r)
+
+	var joined sync.WaitGroup
+	joined.Add(1)
+	go worker(&joined)
+	joined.Wait()
+
+	var other sync.Mutex
+	f := &other
+	f.Lock()
+
+	// Structs with only zero value ready fields are ready too
+	var locks Locks
+	consume(&locks)
+}
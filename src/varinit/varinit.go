@@ -4,18 +4,59 @@ import (
 	"fmt"
 	"go/ast"
 	"go/token"
+	"go/types"
+	"maps"
 	"regexp"
+	"slices"
 	"strings"
 
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/passes/ctrlflow"
 	"golang.org/x/tools/go/cfg"
+	"golang.org/x/tools/go/types/typeutil"
 
 	"github.com/upsun/vinego/src/utils"
 )
 
 type BranchId token.Pos
-type VarId token.Pos
+
+//vinego:check allfields
+type VarId struct {
+	Pos token.Pos
+	// Path of the field (ex: `a.b`) for variables tracked per field, empty if tracked as a whole.
+	// Paths starting with PointeeField are for the memory a pointer variable points to.
+	Field string
+}
+
+// First element of the field path of the memory a pointer points to, ex: `*.a` for `p.a` or `(*p).a`
+const PointeeField = "*"
+
+func isPointeeField(field string) bool {
+	return field == PointeeField || strings.HasPrefix(field, PointeeField+".")
+}
+
+// Whether one id is the other or a field within it
+func (id VarId) Overlaps(other VarId) bool {
+	if id.Pos != other.Pos {
+		return false
+	}
+	return id.Field == "" ||
+		other.Field == "" ||
+		id.Field == other.Field ||
+		strings.HasPrefix(id.Field, other.Field+".") ||
+		strings.HasPrefix(other.Field, id.Field+".")
+}
+
+// Whether other is this id or a field within it.  A pointer variable doesn't contain its pointee.
+func (id VarId) Contains(other VarId) bool {
+	if id.Pos != other.Pos {
+		return false
+	}
+	if id.Field == "" {
+		return !isPointeeField(other.Field)
+	}
+	return id.Field == other.Field || strings.HasPrefix(other.Field, id.Field+".")
+}
 
 type DeclBranch struct {
 	Comment string
@@ -26,46 +67,296 @@ type Decl struct {
 	// If this decl initialization has changed
 	Changed       bool
 	Uninitialized map[BranchId]DeclBranch
+	// Goroutines started on every path that initialize the variable, once joined
+	Pending map[PendingWrite]bool
 }
 
-// check:allfields
+//vinego:check allfields
 type Scope struct {
 	Location      BranchId
 	Comment       string
 	Uninitialized map[VarId]*Decl
 }
 
-// check:allfields
+//vinego:check allfields
+type UninitializedField struct {
+	// Empty if the variable is tracked as a whole
+	Field string
+	// Positions of the branches where the field isn't initialized
+	Branches []string
+	// Positions of goroutines that initialize the field, started but not waited for yet
+	Goroutines []string
+}
+
+// Customizes which variables are tracked and how uses of uninitialized variables are reported.
+//
+//vinego:check allfields
+type Tracker struct {
+	// Returns the fields (paths, like `a.b`) of a new variable to track individually, or nil to
+	// track it as a whole.  Returns false to not track the variable at all.
+	Fields func(p *analysis.Pass, v *types.Var) ([]string, bool)
+	// Creates the message for a use of a variable that may not be initialized
+	Message func(name string, uninitialized []UninitializedField) string
+	// Whether calling the pointer receiver method on a variable initializes it
+	InitializedBy func(p *analysis.Pass, method *types.Func) bool
+}
+
+// Returns the paths of the fields of the struct, descending into fields that are structs
+// themselves.  Nested structs with fields that can't be assigned from the package are a single path.
+// Fields with a type for which skip returns true are left out.  Returns false if the struct itself
+// has fields that can't be assigned.
+func StructFieldPaths(pkg *types.Package, structType *types.Struct, skip func(t types.Type) bool) ([]string, bool) {
+	out := []string{}
+	for i := 0; i < structType.NumFields(); i++ {
+		field := structType.Field(i)
+		if !field.Exported() && field.Pkg() != pkg {
+			return nil, false
+		}
+		if field.Name() == "_" || skip(field.Type()) {
+			continue
+		}
+		if nested, isStruct := field.Type().Underlying().(*types.Struct); isStruct {
+			nestedPaths, assignable := StructFieldPaths(pkg, nested, skip)
+			if assignable && len(nestedPaths) > 0 {
+				for _, nestedPath := range nestedPaths {
+					utils.Append(&out, field.Name()+"."+nestedPath)
+				}
+				continue
+			}
+		}
+		utils.Append(&out, field.Name())
+	}
+	return out, true
+}
+
+// Tracks struct variables field by field, other variables as a whole.  Variables ready to use
+// without initialization (see ReadyToUse), like nil slices or a `sync.Mutex`, aren't tracked.
+// Variables are initialized by calling an initializer method (see IsInitializer).
+func NewDefaultTracker(zeroOk map[string]bool) *Tracker {
+	return &Tracker{
+		Fields: func(p *analysis.Pass, v *types.Var) ([]string, bool) {
+			ready := func(t types.Type) bool {
+				return ReadyToUse(p, zeroOk, t)
+			}
+			if ready(v.Type()) {
+				return nil, false
+			}
+			structType, isStruct := v.Type().Underlying().(*types.Struct)
+			if !isStruct {
+				return nil, true
+			}
+			fields, assignable := StructFieldPaths(p.Pkg, structType, ready)
+			if !assignable || len(fields) == 0 {
+				return nil, true
+			}
+			return fields, true
+		},
+		Message:       defaultMessage,
+		InitializedBy: IsInitializer,
+	}
+}
+
+// Whether the method is marked with `//vinego:check initializer`, meaning it initializes its
+// (pointer) receiver
+func IsInitializer(p *analysis.Pass, method *types.Func) bool {
+	return utils.GetTags(p, method.Origin()).Has("initializer")
+}
+
+func defaultMessage(name string, uninitialized []UninitializedField) string {
+	out := []string{}
+	for _, field := range uninitialized {
+		fieldName := name
+		if field.Field != "" {
+			fieldName += "." + field.Field
+		}
+		if len(field.Goroutines) > 0 {
+			utils.Append(&out, fmt.Sprintf(
+				"`%s` is read before waiting for the goroutines initializing it, this is likely a race:\n%s\n",
+				fieldName,
+				strings.Join(field.Goroutines, "\n"),
+			))
+			continue
+		}
+		utils.Append(&out, fmt.Sprintf(
+			"`%s` hasn't been initialized in the following branches:\n%s\n",
+			fieldName,
+			strings.Join(field.Branches, "\n"),
+		))
+	}
+	return strings.Join(out, "")
+}
+
+// State shared by all evaluations in a pass
+//
+//vinego:check allfields
+type Analysis struct {
+	p         *analysis.Pass
+	flows     *Flows
+	tracker   *Tracker
+	outParams *OutParams
+	reported  map[VarId]bool
+	// Set while iterating towards a fixed point, when states aren't final
+	silent bool
+	// Variables initialized at each signal of the goroutine being started, nil outside of goroutines
+	signalStates map[ast.Node]map[VarId]bool
+	// Evaluations of closures while iterating towards a fixed point, by the closure
+	closures map[*ast.FuncLit][]ClosureResult
+}
+
+// The result of evaluating a closure with an input state
+//
+//vinego:check allfields
+type ClosureResult struct {
+	Input  *Scope
+	Output *Scope
+	// The variables initialized at the signals of the goroutine, see StartGoroutine
+	SignalStates map[ast.Node]map[VarId]bool
+}
+
+func NewAnalysis(p *analysis.Pass, flows *Flows, tracker *Tracker, outParams *OutParams) *Analysis {
+	return &Analysis{
+		p:            p,
+		flows:        flows,
+		tracker:      tracker,
+		outParams:    outParams,
+		reported:     map[VarId]bool{},
+		silent:       false,
+		signalStates: nil,
+		closures:     map[*ast.FuncLit][]ClosureResult{},
+	}
+}
+
+//vinego:check allfields
 type Context struct {
-	p        *analysis.Pass
-	cfgs     *ctrlflow.CFGs
-	scope    *Scope
-	reported map[VarId]bool
+	*Analysis
+	scope *Scope
+	// Block being evaluated, nil outside of functions
+	block *cfg.Block
+	// Closures deferred in the function, nil if not collecting them
+	deferred *[]DeferredFunc
+}
+
+// A closure deferred by a function, evaluated when the function exits
+//
+//vinego:check allfields
+type DeferredFunc struct {
+	Lit *ast.FuncLit
+	// Block containing the defer statement
+	Block *cfg.Block
 }
 
 func DeclIdForUse(p *analysis.Pass, ident *ast.Ident) VarId {
 	obj := p.TypesInfo.Uses[ident]
 	if obj == nil {
-		return VarId(0)
+		return VarId{Pos: 0, Field: ""}
+	}
+	return VarId{Pos: obj.Pos(), Field: ""}
+}
+
+// Returns the variable and field path of a (possibly nested) field selection on a variable, like
+// `x.a.b`.  Promoted fields are expanded to their full path.  Selections through pointers don't
+// count since they don't refer to the variable's own memory, except through a pointer variable
+// itself (`p.a` or `*p`) which gives a path starting with PointeeField.
+func FieldPath(p *analysis.Pass, e ast.Expr) (*ast.Ident, string, bool) {
+	segments := [][]string{}
+	for {
+		switch e1 := ast.Unparen(e).(type) {
+		case *ast.Ident:
+			if len(segments) == 0 {
+				return nil, "", false
+			}
+			path := []string{}
+			for i := range segments {
+				utils.Append(&path, segments[len(segments)-1-i]...)
+			}
+			return e1, strings.Join(path, "."), true
+		case *ast.StarExpr:
+			if _, isIdent := ast.Unparen(e1.X).(*ast.Ident); !isIdent {
+				return nil, "", false
+			}
+			utils.Append(&segments, []string{PointeeField})
+			e = e1.X
+		case *ast.SelectorExpr:
+			sel := p.TypesInfo.Selections[e1]
+			if sel == nil || sel.Kind() != types.FieldVal {
+				return nil, "", false
+			}
+			segment := []string{}
+			t := sel.Recv()
+			if sel.Indirect() {
+				_, isIdent := ast.Unparen(e1.X).(*ast.Ident)
+				pointer, isPointer := t.Underlying().(*types.Pointer)
+				if !isIdent || !isPointer {
+					return nil, "", false
+				}
+				utils.Append(&segment, PointeeField)
+				t = pointer.Elem()
+			}
+			for _, index := range sel.Index() {
+				structType, isStruct := t.Underlying().(*types.Struct)
+				if !isStruct {
+					return nil, "", false
+				}
+				field := structType.Field(index)
+				utils.Append(&segment, field.Name())
+				t = field.Type()
+			}
+			utils.Append(&segments, segment)
+			e = e1.X
+		default:
+			return nil, "", false
+		}
 	}
-	id := VarId(obj.Pos())
-	return id
 }
 
 func (s *Scope) GetUninitialized(p *analysis.Pass, id VarId) *Decl {
 	return s.Uninitialized[id]
 }
 
-func (s *Scope) NewDecl(p *analysis.Pass, ident *ast.Ident) {
-	obj := p.TypesInfo.Defs[ident]
-	if obj == nil {
+// Whether the variable's fields are tracked individually
+func (s *Scope) TracksFields(p *analysis.Pass, ident *ast.Ident) bool {
+	id := DeclIdForUse(p, ident)
+	for other := range s.Uninitialized {
+		if other.Pos == id.Pos && other.Field != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Scope) NewDecl(a *Analysis, ident *ast.Ident) {
+	obj, isVar := a.p.TypesInfo.Defs[ident].(*types.Var)
+	if !isVar {
 		return
 	}
-	id := VarId(obj.Pos())
-	s.Uninitialized[id] = &Decl{
-		Name:          ident.Name,
-		Changed:       false,
-		Uninitialized: map[BranchId]DeclBranch{s.Location: {Comment: s.Comment}},
+	fields, track := a.tracker.Fields(a.p, obj)
+	if !track {
+		return
+	}
+	newDecl := func(field string) {
+		s.Uninitialized[VarId{Pos: obj.Pos(), Field: field}] = &Decl{
+			Name:          ident.Name,
+			Changed:       false,
+			Uninitialized: map[BranchId]DeclBranch{s.Location: {Comment: s.Comment}},
+			Pending:       map[PendingWrite]bool{},
+		}
+	}
+	if fields == nil {
+		newDecl("")
+	}
+	for _, field := range fields {
+		newDecl(field)
+	}
+}
+
+// Marks the variable or field with the id, and all fields within it, initialized
+func (s *Scope) MarkIdInitialized(id VarId) {
+	for other, decl := range s.Uninitialized {
+		if id.Contains(other) {
+			decl.Changed = true
+			decl.Uninitialized = nil
+			decl.Pending = nil
+		}
 	}
 }
 
@@ -74,12 +365,15 @@ func (s *Scope) MarkInitialized(p *analysis.Pass, ident *ast.Ident) {
 	if obj == nil {
 		return
 	}
-	id := VarId(obj.Pos())
-	decl, hasDecl := s.Uninitialized[id]
-	if hasDecl {
-		decl.Changed = true
-		decl.Uninitialized = nil
+	s.MarkIdInitialized(VarId{Pos: obj.Pos(), Field: ""})
+}
+
+func (s *Scope) MarkFieldInitialized(p *analysis.Pass, ident *ast.Ident, field string) {
+	obj := p.TypesInfo.Uses[ident]
+	if obj == nil {
+		return
 	}
+	s.MarkIdInitialized(VarId{Pos: obj.Pos(), Field: field})
 }
 
 // Skips root element -- this is only for when other specific evaluations fall through (i.e. root element has no useful info)
@@ -110,7 +404,7 @@ func EvalVarDecl(c *Context, valSpec *ast.ValueSpec) {
 		}
 	} else {
 		for _, name := range valSpec.Names {
-			c.scope.NewDecl(c.p, name)
+			c.scope.NewDecl(c.Analysis, name)
 		}
 	}
 }
@@ -130,62 +424,133 @@ func EvalVarDeclBlock(c *Context, d *ast.GenDecl) {
 }
 
 func CheckUseByDecl(c *Context, id VarId, name string, reportPos token.Pos) {
-	uninit := c.scope.GetUninitialized(c.p, id)
-	if uninit == nil || len(uninit.Uninitialized) == 0 {
+	if c.silent || c.reported[id] {
 		return
 	}
-	branchStrings := []string{}
-	for branch := range uninit.Uninitialized {
-		utils.Append(&branchStrings, " - "+c.p.Fset.Position(token.Pos(branch)).String())
+	uninitialized := []UninitializedField{}
+	for other, decl := range c.scope.Uninitialized {
+		if !id.Overlaps(other) || len(decl.Uninitialized) == 0 {
+			continue
+		}
+		branchStrings := []string{}
+		for branch := range decl.Uninitialized {
+			utils.Append(&branchStrings, " - "+c.p.Fset.Position(token.Pos(branch)).String())
+		}
+		slices.Sort(branchStrings)
+		goroutineStrings := []string{}
+		for write := range decl.Pending {
+			utils.Append(&goroutineStrings, " - "+c.p.Fset.Position(write.Start).String())
+		}
+		slices.Sort(goroutineStrings)
+		goroutineStrings = slices.Compact(goroutineStrings)
+		utils.Append(&uninitialized, UninitializedField{
+			Field:      other.Field,
+			Branches:   branchStrings,
+			Goroutines: goroutineStrings,
+		})
+	}
+	if len(uninitialized) == 0 {
+		return
 	}
+	slices.SortFunc(uninitialized, func(a, b UninitializedField) int {
+		return strings.Compare(a.Field, b.Field)
+	})
 	c.p.Report(analysis.Diagnostic{
 		Pos:     reportPos,
-		Message: fmt.Sprintf("`%s` hasn't been initialized in the following branches:\n%s\n", uninit.Name, strings.Join(branchStrings, "\n")),
+		Message: c.tracker.Message(name, uninitialized),
 	})
 	c.reported[id] = true
 }
 
 func CheckUse(c *Context, e *ast.Ident) {
+	declId := DeclIdForUse(c.p, e)
+	CheckUseByDecl(c, declId, e.Name, e.Pos())
+}
+
+func CheckFieldUse(c *Context, e *ast.Ident, field string) {
 	declId := DeclIdForUse(c.p, e)
 	if c.reported[declId] {
 		return
 	}
+	declId.Field = field
 	CheckUseByDecl(c, declId, e.Name, e.Pos())
 }
 
 func EvalExpr(c *Context, n ast.Expr) {
 	switch e := n.(type) {
 	case *ast.CallExpr:
-		for _, arg := range e.Args {
+		if lit, join, isGo := GoMethodCall(c, e); isGo {
+			if id, initializes := ReceiverInitialized(c, e.Fun); initializes {
+				c.scope.MarkIdInitialized(id)
+			} else {
+				EvalExpr(c, e.Fun)
+			}
+			StartGoroutine(c, lit, e.Pos(), []Signal{{Join: join, Node: nil}})
+			return
+		}
+		outArgs, unknownArgs := CalleeOutArgs(c, e)
+		written := []VarId{}
+		for i, arg := range e.Args {
 			switch f := arg.(type) {
 			case *ast.FuncLit:
 				// Assume closures passed to a function as arguments will
 				// be called before the function returns.  This will produce
 				// some false negatives but hopefully such cases are rare.
-				resScope := EvalFunc(c.p, c.cfgs, c.cfgs.FuncLit(f), f.Type, []*Scope{c.scope}, c.reported)
+				resScope, _ := EvalClosure(c, f, nil)
 				c.scope.Uninitialized = resScope.Uninitialized
 			default:
-				EvalExpr(c, arg)
+				if id, isPointer := WrittenId(c, arg); isPointer && outArgs[i] {
+					// Written by the call, not read
+					utils.Append(&written, id)
+				} else if unary, isUnary := ast.Unparen(arg).(*ast.UnaryExpr); isUnary && unary.Op == token.AND && !(unknownArgs[i] && c.outParams.Permissive) {
+					// The callee may read through the pointer, so it's a use
+					Recurse(c, unary)
+				} else {
+					EvalExpr(c, arg)
+				}
 			}
 		}
 		switch f := e.Fun.(type) {
 		case *ast.FuncLit:
-			resScope := EvalFunc(c.p, c.cfgs, c.cfgs.FuncLit(f), f.Type, []*Scope{c.scope}, c.reported)
+			resScope, _ := EvalClosure(c, f, nil)
 			c.scope.Uninitialized = resScope.Uninitialized
+		default:
+			if id, initializes := ReceiverInitialized(c, f); initializes {
+				// Takes the address of the variable to initialize it
+				utils.Append(&written, id)
+			} else {
+				// Method receivers and function variables
+				EvalExpr(c, f)
+			}
+		}
+		for _, id := range written {
+			c.scope.MarkIdInitialized(id)
 		}
+		RecordSignal(c, e)
+		Join(c, methodCallReceiver(c, e, waitMethods))
 	case *ast.UnaryExpr:
-		{
-			ident, isIdent := e.X.(*ast.Ident)
-			if !isIdent {
-				goto NotAddr
+		if id, isPointer := WrittenId(c, e); isPointer && e.Op == token.AND {
+			// The pointer could be written through or read later, but only calls are followed
+			if c.outParams.Permissive {
+				c.scope.MarkIdInitialized(id)
 			}
-			if e.Op != token.AND {
-				goto NotAddr
-			}
-			c.scope.MarkInitialized(c.p, ident)
 			return
 		}
-	NotAddr:
+		Recurse(c, e)
+		if e.Op == token.ARROW {
+			// Receiving from a channel joins the goroutines sending to or closing it
+			JoinOne(c, joinKey(c, e.X))
+		}
+	case *ast.SelectorExpr:
+		if ident, field, isField := FieldPath(c.p, e); isField && c.scope.TracksFields(c.p, ident) {
+			CheckFieldUse(c, ident, field)
+			return
+		}
+		if ident, field, isField := MethodFieldPath(c.p, e); isField && c.scope.TracksFields(c.p, ident) {
+			// Promoted methods only use the embedded field
+			CheckFieldUse(c, ident, field)
+			return
+		}
 		Recurse(c, e)
 	case *ast.Ident:
 		CheckUse(c, e)
@@ -194,29 +559,97 @@ func EvalExpr(c *Context, n ast.Expr) {
 	}
 }
 
+// Evaluates the expressions read by the left hand side of an assignment, like indices and the
+// variables in compound assignments
+func EvalAssignTargets(c *Context, s *ast.AssignStmt) {
+	for _, l := range s.Lhs {
+		if s.Tok != token.ASSIGN && s.Tok != token.DEFINE {
+			// Compound assignments like `x += 1` read the old value
+			EvalExpr(c, l)
+			continue
+		}
+		switch l.(type) {
+		case *ast.Ident:
+		default:
+			if ident, _, isField := FieldPath(c.p, l); isField && c.scope.TracksFields(c.p, ident) {
+				continue
+			}
+			EvalExpr(c, l)
+		}
+	}
+}
+
+// Marks the variables and fields assigned by an assignment initialized
+func MarkAssigned(c *Context, s *ast.AssignStmt) {
+	for _, l := range s.Lhs {
+		if ident, isIdent := l.(*ast.Ident); isIdent {
+			c.scope.MarkInitialized(c.p, ident)
+		} else if ident, field, isField := FieldPath(c.p, l); isField && c.scope.TracksFields(c.p, ident) {
+			c.scope.MarkFieldInitialized(c.p, ident, field)
+		}
+	}
+}
+
+// Evaluates the send or receive of a `select` case before the case is chosen: only the channel
+// operand and the sent value are evaluated
+func EvalSelectComm(c *Context, n ast.Stmt) {
+	switch s := n.(type) {
+	case *ast.SendStmt:
+		EvalExpr(c, s.Chan)
+		EvalExpr(c, s.Value)
+	case *ast.ExprStmt:
+		if recv, isRecv := ast.Unparen(s.X).(*ast.UnaryExpr); isRecv {
+			EvalExpr(c, recv.X)
+		}
+	case *ast.AssignStmt:
+		if recv, isRecv := ast.Unparen(s.Rhs[0]).(*ast.UnaryExpr); isRecv {
+			EvalExpr(c, recv.X)
+		}
+	}
+}
+
+// Completes the receive of a chosen `select` case: it joins the goroutines signalling the channel,
+// then the variables of `case v = <-ch` and `case v, ok = <-ch` are assigned
+func EvalSelectCase(c *Context, clause *ast.CommClause) {
+	var recv ast.Expr
+	switch s := clause.Comm.(type) {
+	case *ast.ExprStmt:
+		recv = s.X
+	case *ast.AssignStmt:
+		EvalAssignTargets(c, s)
+		recv = s.Rhs[0]
+	}
+	if recv, isRecv := ast.Unparen(recv).(*ast.UnaryExpr); isRecv && recv.Op == token.ARROW {
+		JoinOne(c, joinKey(c, recv.X))
+	}
+	// A chosen send case signals the goroutines receiving from the channel
+	RecordSignal(c, clause.Comm)
+	if s, isAssign := clause.Comm.(*ast.AssignStmt); isAssign {
+		MarkAssigned(c, s)
+	}
+}
+
 func EvalStmt(c *Context, n ast.Stmt) {
+	if c.flows.SelectComm(n) {
+		EvalSelectComm(c, n)
+		return
+	}
 	switch s := n.(type) {
 	case *ast.DeclStmt:
 		d := s.Decl.(*ast.GenDecl)
 		EvalVarDeclBlock(c, d)
 	case *ast.AssignStmt:
-		for _, l := range s.Lhs {
-			switch l.(type) {
-			case *ast.Ident:
-			default:
-				EvalExpr(c, l)
-			}
+		if assert, isAssert := s.Rhs[0].(*ast.TypeAssertExpr); isAssert && assert.Type == nil {
+			// Type switch guard `t := x.(type)`: only `x` is read.  Each clause declares its own
+			// `t` (see `types.Info.Implicits`), initialized with `x`.
+			EvalExpr(c, assert.X)
+			return
 		}
+		EvalAssignTargets(c, s)
 		for _, r := range s.Rhs {
 			EvalExpr(c, r)
 		}
-		for _, l := range s.Lhs {
-			ident, isIdent := l.(*ast.Ident)
-			if !isIdent {
-				continue
-			}
-			c.scope.MarkInitialized(c.p, ident)
-		}
+		MarkAssigned(c, s)
 	case *ast.GoStmt:
 		for _, arg := range s.Call.Args {
 			EvalExpr(c, arg)
@@ -224,10 +657,10 @@ func EvalStmt(c *Context, n ast.Stmt) {
 		lit, isLit := s.Call.Fun.(*ast.FuncLit)
 		if isLit {
 			// Evaluate the goroutine function as if the captured variables have the
-			// initialization state at the time of forking.  Don't allow initializations
-			// within the goroutine to affect the outer flow though, since the actual
+			// initialization state at the time of forking.  Initializations within the
+			// goroutine only affect the outer flow after joining it, since the actual
 			// execution could happen whenever.
-			EvalFunc(c.p, c.cfgs, c.cfgs.FuncLit(lit), lit.Type, nil, c.reported)
+			StartGoroutine(c, lit, s.Pos(), GoroutineJoins(c, lit))
 		} else {
 			EvalExpr(c, s.Call)
 		}
@@ -237,16 +670,19 @@ func EvalStmt(c *Context, n ast.Stmt) {
 		}
 		lit, isLit := s.Call.Fun.(*ast.FuncLit)
 		if isLit {
-			// Evaluate the goroutine function as if the captured variables have the
-			// initialization state at the time of deferring.  Don't allow initializations
-			// within the function to affect the outer flow though, since the actual
-			// execution could happen whenever.
-			EvalFunc(c.p, c.cfgs, c.cfgs.FuncLit(lit), lit.Type, nil, c.reported)
+			// The function runs when the outer function exits, so it's evaluated with the exit
+			// states once they're known.  Initializations within it don't affect the outer flow.
+			if c.deferred != nil {
+				utils.Append(c.deferred, DeferredFunc{Lit: lit, Block: c.block})
+			}
 		} else {
 			EvalExpr(c, s.Call)
 		}
 	case *ast.ExprStmt:
 		EvalExpr(c, s.X)
+	case *ast.SendStmt:
+		Recurse(c, s)
+		RecordSignal(c, s)
 	default:
 		Recurse(c, s)
 	}
@@ -262,6 +698,15 @@ func MergeScopes(block *cfg.Block, inputs []*Scope) *Scope {
 						Name:          branchDecl.Name,
 						Changed:       false,
 						Uninitialized: map[BranchId]DeclBranch{},
+						Pending:       map[PendingWrite]bool{},
+					}
+					utils.MergeMap(v.Pending, branchDecl.Pending)
+				} else {
+					// Only goroutines started on all paths
+					for write := range v.Pending {
+						if !branchDecl.Pending[write] {
+							delete(v.Pending, write)
+						}
 					}
 				}
 				v.Changed = v.Changed || branchDecl.Changed
@@ -314,56 +759,137 @@ func BlockComment(b *cfg.Block) string {
 	return extractCommentRegexp.FindStringSubmatch(b.String())[1]
 }
 
-func EvalFuncsDepWalk(
-	// Inputs...
-	p *analysis.Pass,
-	cfgs *ctrlflow.CFGs,
+// Whether the scopes have the same initialization state
+func ScopesEqual(a *Scope, b *Scope) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if len(a.Uninitialized) != len(b.Uninitialized) {
+		return false
+	}
+	for vid, aDecl := range a.Uninitialized {
+		bDecl, exists := b.Uninitialized[vid]
+		if !exists ||
+			aDecl.Changed != bDecl.Changed ||
+			len(aDecl.Uninitialized) != len(bDecl.Uninitialized) ||
+			len(aDecl.Pending) != len(bDecl.Pending) {
+			return false
+		}
+		for write := range aDecl.Pending {
+			if !bDecl.Pending[write] {
+				return false
+			}
+		}
+		for branch := range aDecl.Uninitialized {
+			if _, exists := bDecl.Uninitialized[branch]; !exists {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Functions that end the process without running deferred functions, by full name (see
+// `types.Func.FullName`)
+var ExitFuncs = map[string]bool{
+	"os.Exit":               true,
+	"syscall.Exit":          true,
+	"log.Fatal":             true,
+	"log.Fatalf":            true,
+	"log.Fatalln":           true,
+	"(*log.Logger).Fatal":   true,
+	"(*log.Logger).Fatalf":  true,
+	"(*log.Logger).Fatalln": true,
+}
+
+// Whether the block ends with a call that doesn't return (see Flows.MayReturn) but unwinds the
+// goroutine running deferred functions, like `panic`, `runtime.Goexit` or `t.FailNow`, rather than
+// ending the process like `os.Exit` or `log.Fatal`
+func EndsUnwinding(a *Analysis, b *cfg.Block) bool {
+	if len(b.Nodes) == 0 {
+		return false
+	}
+	stmt, isExprStmt := utils.Last(b.Nodes).(*ast.ExprStmt)
+	if !isExprStmt {
+		return false
+	}
+	call, isCall := stmt.X.(*ast.CallExpr)
+	if !isCall || a.flows.MayReturn(call) {
+		return false
+	}
+	if fn, isFunc := typeutil.Callee(a.p.TypesInfo, call).(*types.Func); isFunc && ExitFuncs[fn.Origin().FullName()] {
+		return false
+	}
+	return true
+}
+
+// Returns the live blocks reachable from the block, including itself
+func ReachableBlocks(from *cfg.Block) []*cfg.Block {
+	seen := map[*cfg.Block]bool{from: true}
+	out := []*cfg.Block{from}
+	for i := 0; i < len(out); i++ {
+		for _, s := range out[i].Succs {
+			if s.Live && !seen[s] {
+				seen[s] = true
+				utils.Append(&out, s)
+			}
+		}
+	}
+	return out
+}
+
+// Adds the uninitialized branches from the block's previous state to its new state.  How branches are
+// attributed when merging can change as more of the loop is evaluated, so this makes sure states
+// only grow and the iteration ends.
+func WidenScope(scope *Scope, previous *Scope) {
+	if previous == nil {
+		return
+	}
+	for vid, previousDecl := range previous.Uninitialized {
+		decl, exists := scope.Uninitialized[vid]
+		if !exists {
+			decl = &Decl{
+				Name:          previousDecl.Name,
+				Changed:       false,
+				Uninitialized: map[BranchId]DeclBranch{},
+				Pending:       map[PendingWrite]bool{},
+			}
+			scope.Uninitialized[vid] = decl
+		}
+		decl.Changed = decl.Changed || previousDecl.Changed
+		for write := range decl.Pending {
+			if !previousDecl.Pending[write] {
+				delete(decl.Pending, write)
+			}
+		}
+		if len(previousDecl.Uninitialized) == 0 {
+			continue
+		}
+		if decl.Uninitialized == nil {
+			decl.Uninitialized = map[BranchId]DeclBranch{}
+		}
+		utils.MergeMap(decl.Uninitialized, previousDecl.Uninitialized)
+	}
+}
+
+// Evaluates a block starting from the merged final scopes of its predecessors that have been
+// evaluated so far
+func EvalBlock(
+	a *Analysis,
 	spec *ast.FuncType,
 	inputs []*Scope,
-	reported map[VarId]bool,
-	deps map[*cfg.Block][]*cfg.Block,
-
-	// Outputs...
-	outputs []*Scope,
-	// blocks with no explicit returns (i.e. "return" not "return 4")
-	emptyReturnOutputs []*Scope,
-	hasNamedReturns *bool,
+	preds map[*cfg.Block][]*cfg.Block,
 	blockScopes map[*cfg.Block]*Scope,
-
-	// Target...
 	b *cfg.Block,
+	deferred *[]DeferredFunc,
 ) *Scope {
-	// Placeholder for preventing reprocessing for diamond deps
-	blockScopes[b] = nil
-
-	// # Process all deps
-	var depScopes []*Scope
-	if len(deps[b]) == 0 {
-		depScopes = inputs
-	} else {
-		depScopes = []*Scope{}
-		for _, dep := range deps[b] {
-			depScope, seen := blockScopes[dep]
-			if !seen {
-				depScope = EvalFuncsDepWalk(
-					p,
-					cfgs,
-					spec,
-					inputs,
-					reported,
-					deps,
-					outputs,
-					emptyReturnOutputs,
-					hasNamedReturns,
-					blockScopes,
-					dep,
-				)
-			}
-			if depScope == nil {
-				// Cycle, ex: for/range loops dep back to end of loop; skip
-				continue
-			}
-			utils.Append(&depScopes, depScope)
+	depScopes := []*Scope{}
+	if b.Index == 0 {
+		utils.Append(&depScopes, inputs...)
+	}
+	for _, pred := range preds[b] {
+		if predScope, evaluated := blockScopes[pred]; evaluated {
+			utils.Append(&depScopes, predScope)
 		}
 	}
 	scope := MergeScopes(b, depScopes)
@@ -371,19 +897,26 @@ func EvalFuncsDepWalk(
 	// For first block, also add named returns as vars
 	if b.Index == 0 {
 		for _, name := range utils.NamedReturns(spec) {
-			*hasNamedReturns = true
-			scope.NewDecl(p, name)
+			scope.NewDecl(a, name)
 		}
 	}
 
 	// Process elements
 	c := &Context{
-		p:        p,
-		cfgs:     cfgs,
+		Analysis: a,
 		scope:    scope,
-		reported: reported,
+		block:    b,
+		deferred: deferred,
+	}
+	nodes := b.Nodes
+	if clause, isComm := b.Stmt.(*ast.CommClause); isComm && b.Kind == cfg.KindSelectCaseBody {
+		EvalSelectCase(c, clause)
+		if _, isAssign := clause.Comm.(*ast.AssignStmt); isAssign {
+			// The graph starts the case with the assigned expression, which isn't a use
+			nodes = nodes[1:]
+		}
 	}
-	for _, e0 := range b.Nodes {
+	for _, e0 := range nodes {
 		switch e := e0.(type) {
 		case ast.Stmt:
 			EvalStmt(c, e)
@@ -395,65 +928,80 @@ func EvalFuncsDepWalk(
 			panic("")
 		}
 	}
-
-	// Store final scope state, return
-	blockScopes[b] = scope
 	return scope
 }
 
 func EvalFunc(
-	p *analysis.Pass,
-	cfgs *ctrlflow.CFGs,
+	a *Analysis,
 	flow *cfg.CFG,
 	spec *ast.FuncType,
 	inputs []*Scope,
-	reported map[VarId]bool,
 ) *Scope {
-	// Calculate dependencies from sucessors
-	deps := map[*cfg.Block][]*cfg.Block{}
+	// Calculate predecessors from sucessors
+	preds := map[*cfg.Block][]*cfg.Block{}
 	for _, b := range flow.Blocks {
 		if !b.Live {
 			continue
 		}
-
-		// Record dependencies from successors
 		for _, s := range b.Succs {
 			if !s.Live {
 				continue
 			}
-			if s == b {
+			preds[s] = append(preds[s], b)
+		}
+	}
+
+	// Find the fixed point: re-evaluate blocks until no block's final scope changes.  Loops feed the
+	// state at the end of an iteration back into the loop head, so this propagates initialization
+	// across iterations.  `goto`, labeled `continue` and `break` are edges of the graph too, so a
+	// label is reached with the merged states of every jump to it.  Uses aren't reported until the states are final.
+	blockScopes := map[*cfg.Block]*Scope{}
+	silent := a.silent
+	a.silent = true
+	for changed := true; changed; {
+		changed = false
+		for _, b := range flow.Blocks {
+			if !b.Live {
 				continue
 			}
-			deps[s] = append(deps[s], b)
+			scope := EvalBlock(a, spec, inputs, preds, blockScopes, b, nil)
+			WidenScope(scope, blockScopes[b])
+			if !ScopesEqual(scope, blockScopes[b]) {
+				changed = true
+			}
+			blockScopes[b] = scope
 		}
 	}
+	a.silent = silent
 
-	// Analyze blocks in dep order, starting with all returns
+	// Final evaluation with reporting
 	outputs := []*Scope{}
 	emptyReturnOutputs := []*Scope{} // blocks with no explicit returns (i.e. "return" not "return 4")
-	hasNamedReturns := false
-	blockScopes := map[*cfg.Block]*Scope{}
+	// Outputs and ends of blocks that panic, where deferred functions run
+	deferOutputs := map[*cfg.Block]*Scope{}
+	deferred := []DeferredFunc{}
 	for _, b := range flow.Blocks {
 		if !b.Live {
 			continue
 		}
+		scope := EvalBlock(a, spec, inputs, preds, blockScopes, b, &deferred)
 		if len(b.Succs) > 0 {
 			continue
 		}
-
-		scope := EvalFuncsDepWalk(
-			p,
-			cfgs,
-			spec,
-			inputs,
-			reported,
-			deps,
-			outputs,
-			emptyReturnOutputs,
-			&hasNamedReturns,
-			blockScopes,
-			b,
-		)
+		if b.Return() == nil {
+			// Ends in a call that doesn't return
+			if EndsUnwinding(a, b) {
+				deferOutputs[b] = scope
+			}
+			continue
+		}
+		if len(b.Return().Results) > 0 {
+			// Returning values assigns the named results
+			for _, name := range utils.NamedReturns(spec) {
+				scope.MarkIdInitialized(VarId{Pos: name.Pos(), Field: ""})
+			}
+		}
+		deferOutputs[b] = scope
 		utils.Append(&outputs, scope)
 		if len(b.Nodes) > 0 {
 			switch l := utils.Last(b.Nodes).(type) {
@@ -467,64 +1015,151 @@ func EvalFunc(
 
 	outScope := MergeScopes(nil, outputs)
 
+	// Evaluate deferred closures with the state at the exits where they may have been deferred
+	for _, d := range deferred {
+		deferInputs := []*Scope{}
+		for _, b := range ReachableBlocks(d.Block) {
+			if scope, isOutput := deferOutputs[b]; isOutput {
+				utils.Append(&deferInputs, scope)
+			}
+		}
+		EvalFunc(a, a.flows.FuncLit(d.Lit), d.Lit.Type, deferInputs)
+	}
+
 	// Check named returns for initialization too
-	if hasNamedReturns {
+	if len(utils.NamedReturns(spec)) > 0 {
 		endContext := &Context{
-			p:        p,
-			cfgs:     cfgs,
+			Analysis: a,
 			scope:    MergeScopes(nil, emptyReturnOutputs),
-			reported: reported,
+			block:    nil,
+			deferred: nil,
 		}
 		for _, name := range utils.NamedReturns(spec) {
-			CheckUseByDecl(endContext, VarId(name.Pos()), name.Name, name.Pos())
+			CheckUseByDecl(endContext, VarId{Pos: name.Pos(), Field: ""}, name.Name, name.Pos())
 		}
 	}
 
 	return outScope
 }
 
-func New() *analysis.Analyzer {
+// Returns a copy of the scope that doesn't share any state with it
+func CopyScope(scope *Scope) *Scope {
+	out := &Scope{
+		Location:      scope.Location,
+		Comment:       scope.Comment,
+		Uninitialized: map[VarId]*Decl{},
+	}
+	for vid, decl := range scope.Uninitialized {
+		out.Uninitialized[vid] = &Decl{
+			Name:          decl.Name,
+			Changed:       decl.Changed,
+			Uninitialized: maps.Clone(decl.Uninitialized),
+			Pending:       maps.Clone(decl.Pending),
+		}
+	}
+	return out
+}
+
+// Whether a closure evaluated with the scopes as input gives the same results, including the branches
+// reported for uninitialized variables
+func sameInput(a *Scope, b *Scope) bool {
+	if a.Location != b.Location || a.Comment != b.Comment || !ScopesEqual(a, b) {
+		return false
+	}
+	for vid, aDecl := range a.Uninitialized {
+		if !maps.Equal(aDecl.Uninitialized, b.Uninitialized[vid].Uninitialized) {
+			return false
+		}
+	}
+	return true
+}
+
+// Evaluates a closure, or the function of a goroutine, with the current state as input.  Returns its
+// final state and the variables initialized at each of the signals' nodes.
+//
+// Every iteration of a loop re-evaluates the closures in it, each of which iterates over its own
+// loops.  To avoid this growing exponentially with nesting, results are reused when the closure has
+// already been evaluated with the same state while iterating towards a fixed point.
+func EvalClosure(c *Context, lit *ast.FuncLit, signals []Signal) (*Scope, map[ast.Node]map[VarId]bool) {
+	if c.silent {
+		for _, result := range c.closures[lit] {
+			if sameInput(result.Input, c.scope) {
+				return CopyScope(result.Output), result.SignalStates
+			}
+		}
+	}
+	input := CopyScope(c.scope)
+	outerStates := c.signalStates
+	c.signalStates = map[ast.Node]map[VarId]bool{}
+	for _, signal := range signals {
+		if signal.Node != nil {
+			c.signalStates[signal.Node] = nil
+		}
+	}
+	outScope := EvalFunc(c.Analysis, c.flows.FuncLit(lit), lit.Type, []*Scope{c.scope})
+	states := c.signalStates
+	c.signalStates = outerStates
+	if c.silent {
+		c.closures[lit] = append(c.closures[lit], ClosureResult{
+			Input:        input,
+			Output:       CopyScope(outScope),
+			SignalStates: states,
+		})
+	}
+	return outScope, states
+}
+
+//vinego:check allfields
+type Settings struct {
+	// Consider taking the address of a variable to initialize it, unless it's passed to a function
+	// known to not write through that parameter
+	PermissiveAddressOf bool `json:"permissive_address_of" optional:"1"`
+	// Full names of functions that never return, in addition to those marked with
+	// `//vinego:check noreturn`, ex: `example.com/must.Die` or `(*example.com/log.Logger).Fatal`
+	NoReturn []string `json:"noreturn" optional:"1"`
+	// Types (qualified like `net/http.Client`) ready to use without initialization, in addition to
+	// those marked with `//vinego:check zerook` and known standard library types
+	ZeroOk []string `json:"zerook" optional:"1"`
+	// Recognize legacy `check:name` tags in doc comments, set from the top level setting
+	LegacyDirectives bool `json:"-" optional:"1"`
+}
+
+func New(settings Settings) *analysis.Analyzer {
 	return &analysis.Analyzer{
-		Name:     "varinit",
-		Doc:      "_",
-		Requires: []*analysis.Analyzer{ctrlflow.Analyzer},
+		Name:      "varinit",
+		Doc:       "_",
+		Requires:  []*analysis.Analyzer{ctrlflow.Analyzer},
+		FactTypes: []analysis.Fact{new(OutParamsFact), new(utils.ChecksFact)},
 		Run: func(p *analysis.Pass) (any, error) {
-			cfgs := p.ResultOf[ctrlflow.Analyzer].(*ctrlflow.CFGs)
-			reported := map[VarId]bool{}
+			utils.ScanTags(p, settings.LegacyDirectives)
+			flows := NewFlows(p, p.ResultOf[ctrlflow.Analyzer].(*ctrlflow.CFGs), settings.NoReturn)
+			zeroOk := map[string]bool{}
+			for _, name := range settings.ZeroOk {
+				zeroOk[name] = true
+			}
+			tracker := NewDefaultTracker(zeroOk)
+			scanner := newOutParamsScanner(p, flows, tracker, settings.PermissiveAddressOf)
+			a := NewAnalysis(p, flows, tracker, &OutParams{
+				Get:        scanner.Get,
+				Permissive: settings.PermissiveAddressOf,
+			})
 			for _, file := range p.Files {
-				globalScope := &Scope{
-					Location:      BranchId(file.Pos()),
-					Comment:       "package",
-					Uninitialized: map[VarId]*Decl{},
-				}
-				c := &Context{
-					p:        p,
-					cfgs:     cfgs,
-					scope:    globalScope,
-					reported: reported,
-				}
 				for _, decl := range file.Decls {
-					switch d := decl.(type) {
-					case *ast.FuncDecl:
-						flow := cfgs.FuncDecl(d)
-						if flow != nil {
-							EvalFunc(p, cfgs, flow, d.Type, nil, reported)
-						}
-					case *ast.GenDecl:
-						EvalVarDeclBlock(c, d)
-					default:
-						panic("UNIMPLEMENTED")
+					funcDecl, isFuncDecl := decl.(*ast.FuncDecl)
+					if !isFuncDecl || (funcDecl.Recv == nil && funcDecl.Name.Name == "init") {
+						// Init functions are evaluated with the package level variables
+						continue
 					}
-					for v, info := range globalScope.Uninitialized {
-						if len(info.Uninitialized) > 0 {
-							p.Report(analysis.Diagnostic{
-								Pos:     token.Pos(v),
-								Message: "This variable was never explicitly initialized",
-							})
-						}
+					if flow := flows.FuncDecl(funcDecl); flow != nil {
+						EvalFunc(a, flow, funcDecl.Type, nil)
 					}
 				}
 			}
+			EvalGlobals(a)
+			// Export facts for functions not called in the package too
+			for fn := range scanner.decls {
+				scanner.Get(fn)
+			}
 			return nil, nil
 		},
 	}
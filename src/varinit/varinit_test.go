@@ -7,5 +7,13 @@ import (
 )
 
 func TestAnalyzers(t *testing.T) {
-	testutils.RunTests(t, New(), nil)
+	testutils.RunTests(t, New(Settings{}), nil)
+}
+
+func TestSettings(t *testing.T) {
+	testutils.RunTestsIn(t, "testdata/settings", New(Settings{
+		PermissiveAddressOf: true,
+		NoReturn:            []string{"noreturnsettingsok.fail"},
+		ZeroOk:              []string{"zerooksettingsok.Registry"},
+	}), nil)
 }
@@ -0,0 +1,150 @@
+package varinit
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/upsun/vinego/src/utils"
+)
+
+// Standard library types whose zero value is ready to use
+var ZeroOkTypes = map[string]bool{
+	"bytes.Buffer":                     true,
+	"golang.org/x/sync/errgroup.Group": true,
+	"strings.Builder":                  true,
+	"sync.Map":                         true,
+	"sync.Mutex":                       true,
+	"sync.Once":                        true,
+	"sync.Pool":                        true,
+	"sync.RWMutex":                     true,
+	"sync.WaitGroup":                   true,
+	"sync/atomic.Bool":                 true,
+	"sync/atomic.Int32":                true,
+	"sync/atomic.Int64":                true,
+	"sync/atomic.Pointer":              true,
+	"sync/atomic.Uint32":               true,
+	"sync/atomic.Uint64":               true,
+	"sync/atomic.Uintptr":              true,
+	"sync/atomic.Value":                true,
+}
+
+// Whether the type's zero value is ready to use: a known standard library type, one in the
+// configured set, or one marked with `//vinego:check zerook`
+func ZeroOk(p *analysis.Pass, configured map[string]bool, t types.Type) bool {
+	named, isNamed := types.Unalias(t).(*types.Named)
+	if !isNamed {
+		return false
+	}
+	obj := named.Origin().Obj()
+	name := utils.QualifiedName(obj)
+	return ZeroOkTypes[name] || configured[name] || utils.GetTags(p, obj).Has("zerook")
+}
+
+// Whether a variable of the type is ready to use without initialization, so isn't tracked: slices,
+// zero value ready types (see ZeroOk), and structs with only such fields
+func ReadyToUse(p *analysis.Pass, configured map[string]bool, t types.Type) bool {
+	if _, isSlice := t.Underlying().(*types.Slice); isSlice {
+		return true
+	}
+	if ZeroOk(p, configured, t) {
+		return true
+	}
+	structType, isStruct := t.Underlying().(*types.Struct)
+	if !isStruct || structType.NumFields() == 0 {
+		return false
+	}
+	for i := 0; i < structType.NumFields(); i++ {
+		if !ReadyToUse(p, configured, structType.Field(i).Type()) {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns the variable or field a method call implicitly takes the address of, like `x` in
+// `x.Init()`, if the tracker considers calling the method to initialize it
+func ReceiverInitialized(c *Context, fun ast.Expr) (VarId, bool) {
+	none := VarId{Pos: 0, Field: ""}
+	sel, isSel := ast.Unparen(fun).(*ast.SelectorExpr)
+	if !isSel {
+		return none, false
+	}
+	selection := c.p.TypesInfo.Selections[sel]
+	if selection == nil || selection.Kind() != types.MethodVal {
+		return none, false
+	}
+	method, isFunc := selection.Obj().(*types.Func)
+	if !isFunc {
+		return none, false
+	}
+	recv := method.Signature().Recv()
+	if recv == nil {
+		return none, false
+	}
+	if _, isPointerRecv := recv.Type().Underlying().(*types.Pointer); !isPointerRecv {
+		return none, false
+	}
+	if _, isPointer := c.p.TypesInfo.TypeOf(sel.X).Underlying().(*types.Pointer); isPointer {
+		// Not the variable's own memory
+		return none, false
+	}
+	if !c.tracker.InitializedBy(c.p, method) {
+		return none, false
+	}
+	ident, path, isPath := methodReceiverPath(c.p, sel, selection)
+	if !isPath || len(path) > 0 && !c.scope.TracksFields(c.p, ident) {
+		return none, false
+	}
+	id := DeclIdForUse(c.p, ident)
+	id.Field = strings.Join(path, ".")
+	return id, true
+}
+
+// Like FieldPath for the receiver of a method value promoted through embedded fields, like
+// `x.Mutex` for `x.Lock` where `x` embeds a `sync.Mutex`.  Returns false for methods declared on
+// the type itself and receivers that aren't a variable's own memory.
+func MethodFieldPath(p *analysis.Pass, sel *ast.SelectorExpr) (*ast.Ident, string, bool) {
+	selection := p.TypesInfo.Selections[sel]
+	if selection == nil || selection.Kind() != types.MethodVal || len(selection.Index()) < 2 {
+		return nil, "", false
+	}
+	if _, isPointer := p.TypesInfo.TypeOf(sel.X).Underlying().(*types.Pointer); isPointer {
+		return nil, "", false
+	}
+	ident, path, isPath := methodReceiverPath(p, sel, selection)
+	if !isPath {
+		return nil, "", false
+	}
+	return ident, strings.Join(path, "."), true
+}
+
+// Returns the variable and field path a method's receiver is in, following the fields embedding
+// promoted methods
+func methodReceiverPath(p *analysis.Pass, sel *ast.SelectorExpr, selection *types.Selection) (*ast.Ident, []string, bool) {
+	embedded := []string{}
+	t := selection.Recv()
+	for _, index := range selection.Index()[:len(selection.Index())-1] {
+		structType, isStruct := t.Underlying().(*types.Struct)
+		if !isStruct {
+			return nil, nil, false
+		}
+		field := structType.Field(index)
+		utils.Append(&embedded, field.Name())
+		t = field.Type()
+	}
+	var ident *ast.Ident
+	path := []string{}
+	if xIdent, isIdent := ast.Unparen(sel.X).(*ast.Ident); isIdent {
+		ident = xIdent
+	} else if xIdent, field, isField := FieldPath(p, sel.X); isField {
+		ident = xIdent
+		utils.Append(&path, field)
+	} else {
+		return nil, nil, false
+	}
+	utils.Append(&path, embedded...)
+	return ident, path, true
+}
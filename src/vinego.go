@@ -6,14 +6,21 @@ import (
 	"github.com/golangci/plugin-module-register/register"
 	"github.com/upsun/vinego/src/allfields"
 	"github.com/upsun/vinego/src/capturederr"
+	"github.com/upsun/vinego/src/directives"
 	"github.com/upsun/vinego/src/explicitcast"
 	"github.com/upsun/vinego/src/varinit"
 )
 
 type Settings struct {
-	EnableVarinit      bool `json:"enable_varinit"`
-	EnableExplicitcast bool `json:"enable_explicitcast"`
-	EnableCapturedErr  bool `json:"enable_capturederr"`
+	EnableVarinit      bool                  `json:"enable_varinit"`
+	EnableExplicitcast bool                  `json:"enable_explicitcast"`
+	EnableCapturedErr  bool                  `json:"enable_capturederr"`
+	Allfields          allfields.Settings    `json:"allfields"`
+	Varinit            varinit.Settings      `json:"varinit"`
+	Explicitcast       explicitcast.Settings `json:"explicitcast"`
+	// Recognize `check:name` tags anywhere in doc comments, in addition to `//vinego:check name`.
+	// Deprecated tags are still recognized when unset.
+	LegacyDirectives *bool `json:"legacy_directives"`
 }
 
 type Vinego struct {
@@ -22,12 +29,22 @@ type Vinego struct {
 
 func (f *Vinego) BuildAnalyzers() ([]*analysis.Analyzer, error) {
 	out := []*analysis.Analyzer{}
-	out = append(out, allfields.New())
+	legacyDirectives := f.settings.LegacyDirectives == nil || *f.settings.LegacyDirectives
+	out = append(out, directives.New(directives.Settings{
+		LegacyDirectives: legacyDirectives,
+	}))
+	allfieldsSettings := f.settings.Allfields
+	allfieldsSettings.LegacyDirectives = legacyDirectives
+	out = append(out, allfields.New(allfieldsSettings))
+	varinitSettings := f.settings.Varinit
+	varinitSettings.LegacyDirectives = legacyDirectives
 	if f.settings.EnableVarinit {
-		out = append(out, varinit.New())
+		out = append(out, varinit.New(varinitSettings))
 	}
+	explicitcastSettings := f.settings.Explicitcast
+	explicitcastSettings.LegacyDirectives = legacyDirectives
 	if f.settings.EnableExplicitcast {
-		out = append(out, explicitcast.New())
+		out = append(out, explicitcast.New(explicitcastSettings))
 	}
 	if f.settings.EnableCapturedErr {
 		out = append(out, capturederr.New())